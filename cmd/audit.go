@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log",
+	Long: `Inspect the audit log.
+
+Account, role, submission and data changes made through the API or
+this command line tool are recorded in the audit log.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		auditListCmd.Run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
+
+// cliActor identifies the person running the command line tool in the audit log
+func cliActor() string {
+	if current, err := user.Current(); err == nil {
+		return "cli:" + current.Username
+	}
+	return "cli:" + os.Getenv("USER")
+}
+
+// recordAudit adds an entry to the audit log for a change made on the command line
+func recordAudit(db *sql.DB, action, target string, before, after interface{}) {
+	entry, err := models.NewAuditEntry(cliActor(), action, target, before, after)
+	if err != nil {
+		panic(fmt.Errorf("Error creating audit entry: %s", err))
+	}
+
+	auditModel := postgres.AuditModel{DB: db}
+	err = auditModel.Record(entry)
+	if err != nil {
+		panic(fmt.Errorf("Error recording audit entry: %s", err))
+	}
+}
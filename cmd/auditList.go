@@ -0,0 +1,93 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+var (
+	auditActor  string
+	auditAction string
+	auditTarget string
+	auditSince  string
+	auditLimit  int
+)
+
+// auditListCmd represents the audit list command
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List audit log entries",
+	Long: `List audit log entries, newest first.
+
+Entries can be filtered by actor, action, target and date.
+Actors are user ids, or cli:<login> for changes made on the command line.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := models.AuditFilter{
+			Actor:  auditActor,
+			Action: auditAction,
+			Target: auditTarget,
+			Limit:  auditLimit,
+		}
+		if auditSince != "" {
+			since, err := time.Parse("2006-01-02", auditSince)
+			if err != nil {
+				panic(fmt.Errorf("Error parsing --since: %s", err))
+			}
+			filter.Since = since
+		}
+
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		auditModel := postgres.AuditModel{DB: db}
+		entries, err := auditModel.List(filter)
+		if err != nil {
+			panic(fmt.Errorf("Error listing audit log: %s", err))
+		}
+
+		fmt.Printf("Time\tActor\tAction\tTarget\tBefore\tAfter\n")
+		for _, entry := range entries {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\n", entry.Timestamp.Format("2006-01-02 15:04:05"), entry.Actor,
+				entry.Action, entry.Target, formatAuditValue(entry.Before), formatAuditValue(entry.After))
+		}
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditListCmd)
+
+	auditListCmd.Flags().StringVar(&auditActor, "actor", "", "Only list entries by this actor")
+	auditListCmd.Flags().StringVar(&auditAction, "action", "", "Only list entries of this action, e.g. user.roles")
+	auditListCmd.Flags().StringVar(&auditTarget, "target", "", "Only list entries for this target, e.g. a user id")
+	auditListCmd.Flags().StringVar(&auditSince, "since", "", "Only list entries from this date (YYYY-MM-DD) on")
+	auditListCmd.Flags().IntVarP(&auditLimit, "limit", "n", 100, "Maximum number of entries, 0 for all")
+}
+
+func formatAuditValue(value []byte) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return string(value)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var exportOutput string
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export MIBiG data",
+	Long: `Export MIBiG data.
+
+Dump the MIBiG database in formats used by external tools.`,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.PersistentFlags().StringVarP(&exportOutput, "output", "o", "-", "File to write to, '-' for stdout")
+}
+
+func openExportOutput() (io.WriteCloser, error) {
+	if exportOutput == "" || exportOutput == "-" {
+		return os.Stdout, nil
+	}
+	handle, err := os.Create(exportOutput)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening %s: %s", exportOutput, err)
+	}
+	return handle, nil
+}
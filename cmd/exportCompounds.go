@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/export"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+	"secondarymetabolites.org/mibig-api/pkg/queries"
+)
+
+var (
+	compoundFormat string
+	compoundQuery  string
+)
+
+// exportCompoundsCmd represents the export compounds command
+var exportCompoundsCmd = &cobra.Command{
+	Use:   "compounds",
+	Short: "Export MIBiG compound structures",
+	Long: `Export MIBiG compound structures.
+
+Writes all compounds with a known structure as SMILES TSV or SD file,
+optionally limited to the entries matching a search query, e.g.
+  mibig-api export compounds --query "NRP AND Streptomyces"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, ok := export.CompoundContentTypes[compoundFormat]; !ok {
+			panic(fmt.Errorf("Invalid format %s, use 'smiles' or 'sdf'", compoundFormat))
+		}
+
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		mibigModel := postgres.MibigModel{DB: db}
+
+		var entry_ids []int
+		if compoundQuery != "" {
+			query, err := queries.NewQueryFromString(compoundQuery)
+			if err != nil {
+				panic(fmt.Errorf("Error parsing query: %s", err))
+			}
+			entry_ids, err = mibigModel.Search(query.Terms)
+			if err != nil {
+				panic(fmt.Errorf("Error running query: %s", err))
+			}
+			if entry_ids == nil {
+				entry_ids = []int{}
+			}
+		}
+
+		entries, err := mibigModel.Entries(entry_ids)
+		if err != nil {
+			panic(fmt.Errorf("Error loading entries: %s", err))
+		}
+
+		records, err := export.CompoundRecords(entries)
+		if err != nil {
+			panic(fmt.Errorf("Error collecting compounds: %s", err))
+		}
+
+		out, err := openExportOutput()
+		if err != nil {
+			panic(err)
+		}
+		defer out.Close()
+
+		if err = export.WriteCompounds(out, records, compoundFormat); err != nil {
+			panic(fmt.Errorf("Error writing compounds: %s", err))
+		}
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportCompoundsCmd)
+
+	exportCompoundsCmd.Flags().StringVarP(&compoundFormat, "format", "f", "smiles", "Output format, 'smiles' or 'sdf'")
+	exportCompoundsCmd.Flags().StringVarP(&compoundQuery, "query", "q", "", "Only export compounds of entries matching this search")
+}
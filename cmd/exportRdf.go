@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/export"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+var rdfFormat string
+
+// exportRdfCmd represents the export rdf command
+var exportRdfCmd = &cobra.Command{
+	Use:   "rdf",
+	Short: "Export MIBiG as RDF",
+	Long: `Export MIBiG as RDF.
+
+Writes entries, compounds, taxa, publications and biosynthetic types
+as Turtle or N-Triples, for loading into a triple store.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, ok := export.RDFContentTypes[rdfFormat]; !ok {
+			panic(fmt.Errorf("Invalid format %s, use 'turtle' or 'ntriples'", rdfFormat))
+		}
+
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		mibigModel := postgres.MibigModel{DB: db}
+		entries, err := mibigModel.Entries(nil)
+		if err != nil {
+			panic(fmt.Errorf("Error loading entries: %s", err))
+		}
+
+		graph, err := export.EntriesToGraph(entries)
+		if err != nil {
+			panic(fmt.Errorf("Error converting entries: %s", err))
+		}
+
+		out, err := openExportOutput()
+		if err != nil {
+			panic(err)
+		}
+		defer out.Close()
+
+		if err = export.WriteRDF(out, graph, rdfFormat); err != nil {
+			panic(fmt.Errorf("Error writing RDF: %s", err))
+		}
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportRdfCmd)
+
+	exportRdfCmd.Flags().StringVarP(&rdfFormat, "format", "f", "turtle", "Output format, 'turtle' or 'ntriples'")
+}
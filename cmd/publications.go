@@ -0,0 +1,37 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// publicationsCmd represents the publications command
+var publicationsCmd = &cobra.Command{
+	Use:   "publications",
+	Short: "Manage the local publication cache",
+	Long: `Manage the local publication cache.
+
+Publication metadata used for reference exports is served from a local
+table instead of querying PubMed on every request.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		publicationsMissingCmd.Run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(publicationsCmd)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+	"secondarymetabolites.org/mibig-api/pkg/pubmed"
+)
+
+// publicationsLoadCmd represents the publications load command
+var publicationsLoadCmd = &cobra.Command{
+	Use:   "load <pubmed.xml> [<pubmed.xml>...]",
+	Short: "Load publications from PubMed XML files",
+	Long: `Load publications from PubMed XML files.
+
+The files are expected to be in the PubmedArticleSet format returned by
+NCBI efetch, e.g. for all publications missing from the cache:
+  mibig-api publications missing | grep '^pubmed:' | cut -d: -f2 | \
+    epost -db pubmed | efetch -format xml > pubmed.xml
+Existing publications are updated.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		publicationModel := postgres.PublicationModel{DB: db}
+
+		for _, filename := range args {
+			handle, err := os.Open(filename)
+			if err != nil {
+				panic(fmt.Errorf("Error opening %s: %s", filename, err))
+			}
+
+			publications, err := pubmed.Parse(handle)
+			handle.Close()
+			if err != nil {
+				panic(fmt.Errorf("Error parsing %s: %s", filename, err))
+			}
+
+			if err = publicationModel.Upsert(publications); err != nil {
+				panic(fmt.Errorf("Error storing publications: %s", err))
+			}
+			recordAudit(db, models.AuditPublicationsLoad, filename, nil, map[string]int{"publications": len(publications)})
+			fmt.Printf("Loaded %d publications from %s\n", len(publications), filename)
+		}
+	},
+}
+
+func init() {
+	publicationsCmd.AddCommand(publicationsLoadCmd)
+}
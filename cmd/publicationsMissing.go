@@ -0,0 +1,55 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// publicationsMissingCmd represents the publications missing command
+var publicationsMissingCmd = &cobra.Command{
+	Use:   "missing",
+	Short: "List publications missing from the cache",
+	Long: `List publications missing from the cache.
+
+Prints the ids of all publications cited by MIBiG entries that have not
+been loaded into the local publication cache yet.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		publicationModel := postgres.PublicationModel{DB: db}
+
+		missing, err := publicationModel.Missing()
+		if err != nil {
+			panic(fmt.Errorf("Error listing missing publications: %s", err))
+		}
+
+		for _, id := range missing {
+			fmt.Println(id)
+		}
+	},
+}
+
+func init() {
+	publicationsCmd.AddCommand(publicationsMissingCmd)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// roleCmd represents the role command
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Manage MIBiG user roles",
+	Long: `Manage MIBiG user roles.
+
+Roles are assigned to users with "user edit add-role" and "user edit remove-role".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		roleListCmd.Run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(roleCmd)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// roleAddCmd represents the role add command
+var roleAddCmd = &cobra.Command{
+	Use:   "add <name> <description>",
+	Short: "Add a role",
+	Long: `Add a role.
+
+Access to the API is controlled by the admin, curator, submitter and guest roles.
+Other roles can be assigned to users, but don't grant access by themselves.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		description := args[1]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		roleModel := postgres.RoleModel{DB: db}
+
+		roleId, err := roleModel.Add(name, description)
+		if err != nil {
+			panic(fmt.Errorf("Error adding role %s: %s", name, err))
+		}
+
+		recordAudit(db, models.AuditRoleCreate, name, nil, map[string]interface{}{
+			"id": roleId, "name": name, "description": description,
+		})
+
+		fmt.Printf("Added role %s with id %d\n", name, roleId)
+	},
+}
+
+func init() {
+	roleCmd.AddCommand(roleAddCmd)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+var roleDeleteForce bool
+
+// roleDeleteCmd represents the role delete command
+var roleDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a role",
+	Long: `Delete a role.
+
+Roles still assigned to users are only deleted with --force,
+which also removes the role from all its members.
+The built-in roles controlling API access can't be deleted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if len(utils.IntersectString([]string{name}, models.RoleHierarchy)) > 0 {
+			panic(fmt.Errorf("Error deleting role %s: built-in roles can't be deleted", name))
+		}
+
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		roleModel := postgres.RoleModel{DB: db}
+
+		role, err := roleModel.Get(name)
+		if err != nil {
+			panic(fmt.Errorf("Error reading role %s: %s", name, err))
+		}
+
+		members, err := roleModel.Members(name)
+		if err != nil {
+			panic(fmt.Errorf("Error listing members of %s: %s", name, err))
+		}
+
+		if len(members) > 0 && !roleDeleteForce {
+			panic(fmt.Errorf("Error deleting role %s: still assigned to %d users, use --force to delete anyway", name, len(members)))
+		}
+
+		err = roleModel.Delete(name)
+		if err != nil {
+			panic(fmt.Errorf("Error deleting role %s: %s", name, err))
+		}
+
+		memberIds := make([]string, 0, len(members))
+		for _, member := range members {
+			memberIds = append(memberIds, member.Id)
+		}
+		recordAudit(db, models.AuditRoleDelete, name, map[string]interface{}{
+			"id": role.Id, "name": role.Name, "description": role.Description, "members": memberIds,
+		}, nil)
+
+		fmt.Printf("Deleted role %s, removing it from %d users\n", name, len(members))
+	},
+}
+
+func init() {
+	roleCmd.AddCommand(roleDeleteCmd)
+
+	roleDeleteCmd.Flags().BoolVarP(&roleDeleteForce, "force", "f", false, "Delete the role even if users still hold it")
+}
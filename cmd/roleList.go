@@ -0,0 +1,59 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// roleListCmd represents the role list command
+var roleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all roles",
+	Long: `List all roles.
+
+List roles along with the number of users holding them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		roleModel := postgres.RoleModel{DB: db}
+
+		roles, err := roleModel.List()
+		if err != nil {
+			panic(fmt.Errorf("Error listing roles: %s", err))
+		}
+
+		fmt.Printf("ID\tName\tUsers\tDescription\n")
+		for _, role := range roles {
+			count, err := roleModel.UserCount(role.Name)
+			if err != nil {
+				panic(fmt.Errorf("Error counting users of %s: %s", role.Name, err))
+			}
+			fmt.Printf("%d\t%s\t%d\t%s\n", role.Id, role.Name, count, role.Description)
+		}
+	},
+}
+
+func init() {
+	roleCmd.AddCommand(roleListCmd)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// roleShowCmd represents the role show command
+var roleShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a role and its members",
+	Long: `Show a role and its members.
+
+Members are listed with their user id, email, name and whether their account is active.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		roleModel := postgres.RoleModel{DB: db}
+
+		role, err := roleModel.Get(name)
+		if err != nil {
+			panic(fmt.Errorf("Error reading role %s: %s", name, err))
+		}
+
+		members, err := roleModel.Members(name)
+		if err != nil {
+			panic(fmt.Errorf("Error listing members of %s: %s", name, err))
+		}
+
+		fmt.Printf("Role:\t%s\nID:\t%d\nDescription:\t%s\nMembers:\t%d\n", role.Name, role.Id, role.Description, len(members))
+		if len(members) == 0 {
+			return
+		}
+
+		fmt.Printf("\nID\tEmail\tName\tActive\n")
+		for _, member := range members {
+			fmt.Printf("%s\t%s\t%s\t%t\n", member.Id, member.Email, member.Name, member.Active)
+		}
+	},
+}
+
+func init() {
+	roleCmd.AddCommand(roleShowCmd)
+}
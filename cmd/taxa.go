@@ -0,0 +1,34 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// taxaCmd represents the taxa command
+var taxaCmd = &cobra.Command{
+	Use:   "taxa",
+	Short: "Manage the taxonomy of MIBiG entries",
+	Long: `Manage the taxonomy of MIBiG entries.
+
+The lineages in mibig.taxa are resolved from a local copy of the NCBI
+taxonomy dump, available from https://ftp.ncbi.nlm.nih.gov/pub/taxonomy/`,
+}
+
+func init() {
+	rootCmd.AddCommand(taxaCmd)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+	"secondarymetabolites.org/mibig-api/pkg/taxdump"
+)
+
+var (
+	taxdumpNames    string
+	taxdumpNodes    string
+	taxdumpMerged   string
+	taxdumpDelnodes string
+	taxaDryRun      bool
+)
+
+// taxaImportCmd represents the taxa import command
+var taxaImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import lineages from an NCBI taxonomy dump",
+	Long: `Import lineages from an NCBI taxonomy dump.
+
+Resolves every tax id referenced by a MIBiG entry using names.dmp and nodes.dmp.
+Pass merged.dmp and delnodes.dmp to report tax ids that have been merged or
+deleted. Merged tax ids keep their entry in mibig.taxa, filled with the lineage
+of the taxon they were merged into.
+
+With --dry-run, only the differences to the stored lineages are printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dump := taxdump.New()
+		readTaxdumpFile(taxdumpNames, dump.ReadNames)
+		readTaxdumpFile(taxdumpNodes, dump.ReadNodes)
+		readTaxdumpFile(taxdumpMerged, dump.ReadMerged)
+		readTaxdumpFile(taxdumpDelnodes, dump.ReadDelnodes)
+
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		taxaModel := postgres.TaxaModel{DB: db}
+
+		ids, err := taxaModel.ReferencedIds()
+		if err != nil {
+			panic(fmt.Errorf("Error listing referenced tax ids: %s", err))
+		}
+
+		stored, err := taxaModel.Get(ids)
+		if err != nil {
+			panic(fmt.Errorf("Error loading stored taxa: %s", err))
+		}
+		storedById := make(map[int]*models.Taxon, len(stored))
+		for i := range stored {
+			storedById[stored[i].TaxID] = &stored[i]
+		}
+
+		var updated []models.Taxon
+		added := 0
+		for _, id := range ids {
+			taxon, err := dump.Taxon(id)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%d: %s\n", id, err)
+				continue
+			}
+			if newId, ok := dump.MergedInto(id); ok {
+				fmt.Fprintf(os.Stderr, "%d: merged into %d\n", id, newId)
+			}
+
+			old, ok := storedById[id]
+			if !ok {
+				fmt.Printf("%d: new taxon %s\n", id, taxon.Name)
+				updated = append(updated, *taxon)
+				added++
+				continue
+			}
+
+			changes := taxdump.Diff(old, taxon)
+			for _, change := range changes {
+				fmt.Printf("%d: %s: %q -> %q\n", id, change.Field, change.Old, change.New)
+			}
+			if len(changes) > 0 {
+				updated = append(updated, *taxon)
+			}
+		}
+
+		if taxaDryRun {
+			fmt.Printf("Would update %d of %d taxa\n", len(updated), len(ids))
+			return
+		}
+
+		if err = taxaModel.Upsert(updated); err != nil {
+			panic(fmt.Errorf("Error storing taxa: %s", err))
+		}
+		recordAudit(db, models.AuditTaxaImport, "taxa", nil,
+			map[string]int{"referenced": len(ids), "added": added, "changed": len(updated) - added})
+		fmt.Printf("Updated %d of %d taxa\n", len(updated), len(ids))
+	},
+}
+
+func readTaxdumpFile(filename string, read func(io.Reader) error) {
+	if filename == "" {
+		return
+	}
+
+	handle, err := os.Open(filename)
+	if err != nil {
+		panic(fmt.Errorf("Error opening %s: %s", filename, err))
+	}
+	defer handle.Close()
+
+	if err = read(handle); err != nil {
+		panic(fmt.Errorf("Error parsing %s: %s", filename, err))
+	}
+}
+
+func init() {
+	taxaCmd.AddCommand(taxaImportCmd)
+
+	taxaImportCmd.Flags().StringVar(&taxdumpNames, "names", "", "Path to names.dmp")
+	taxaImportCmd.Flags().StringVar(&taxdumpNodes, "nodes", "", "Path to nodes.dmp")
+	taxaImportCmd.Flags().StringVar(&taxdumpMerged, "merged", "", "Path to merged.dmp")
+	taxaImportCmd.Flags().StringVar(&taxdumpDelnodes, "delnodes", "", "Path to delnodes.dmp")
+	taxaImportCmd.Flags().BoolVar(&taxaDryRun, "dry-run", false, "Print the differences without writing them")
+	taxaImportCmd.MarkFlagRequired("names")
+	taxaImportCmd.MarkFlagRequired("nodes")
+}
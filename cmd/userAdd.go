@@ -57,6 +57,8 @@ Required parameters can be passed on the command line, or added in the interacti
 			Public:      public,
 			GDPRConsent: gdpr_consent,
 			Active:      active,
+			// Admins vouch for the addresses of the accounts they add
+			EmailVerified: true,
 		}
 
 		db, err := InitDb()
@@ -84,6 +86,8 @@ Required parameters can be passed on the command line, or added in the interacti
 		if err != nil {
 			panic(fmt.Errorf("Error adding user: %s", err))
 		}
+
+		recordAudit(db, models.AuditUserCreate, user.Id, nil, user.Audit())
 	},
 }
 
@@ -48,6 +48,7 @@ Only roles the user doesn't have yet will be added.`,
 			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
 		}
 
+		before := user.Audit()
 		oldRoleNames := models.RolesToStrings(user.Roles)
 
 		roleNames := utils.UnionString(oldRoleNames, newRoleNames)
@@ -62,6 +63,7 @@ Only roles the user doesn't have yet will be added.`,
 			panic(fmt.Errorf("Error updating user: %s", err))
 		}
 
+		recordAudit(db, models.AuditUserRoles, user.Id, before, user.Audit())
 	},
 }
 
@@ -0,0 +1,37 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// userApikeyCmd represents the apikey command
+var userApikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys of MIBiG users",
+	Long: `Manage API keys of MIBiG users.
+
+API keys allow scripts to access the API on behalf of a user,
+limited to a subset of the user's roles.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userApikeyCmd)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+var (
+	apikeyScopes []string
+	apikeyDays   int
+)
+
+// userApikeyCreateCmd represents the apikey create command
+var userApikeyCreateCmd = &cobra.Command{
+	Use:   "create <email> <name>",
+	Short: "Create an API key for a user",
+	Long: `Create an API key for a user.
+
+The key is printed once and can't be retrieved later.
+Scopes default to all of the user's current roles.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		email := args[0]
+		name := args[1]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		user, err := postgres.NewSubmitterModel(db).Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
+		}
+
+		userRoles := models.RolesToStrings(user.Roles)
+		scopes := utils.UnionString(nil, apikeyScopes)
+		if len(scopes) == 0 {
+			scopes = userRoles
+		}
+		if len(utils.IntersectString(scopes, userRoles)) != len(scopes) {
+			panic(fmt.Errorf("Error creating API key: scopes %v are not all roles of %s", scopes, email))
+		}
+		if apikeyDays <= 0 {
+			panic(fmt.Errorf("Error creating API key: --days must be positive"))
+		}
+
+		key, secret, err := (&postgres.ApiKeyModel{DB: db}).Create(user.Id, name, scopes, time.Now().AddDate(0, 0, apikeyDays))
+		if err != nil {
+			panic(fmt.Errorf("Error creating API key: %s", err))
+		}
+
+		recordAudit(db, models.AuditApiKeyCreate, user.Id, nil, map[string]interface{}{
+			"id": key.Id, "scopes": key.Scopes, "expires": key.Expires,
+		})
+
+		fmt.Printf("Created API key %s, expiring %s\n", key.Id, formatKeyTime(key.Expires))
+		fmt.Println(secret)
+	},
+}
+
+func init() {
+	userApikeyCmd.AddCommand(userApikeyCreateCmd)
+
+	userApikeyCreateCmd.Flags().StringSliceVarP(&apikeyScopes, "scope", "s", nil, "Roles the key grants, defaults to all the user's roles")
+	userApikeyCreateCmd.Flags().IntVarP(&apikeyDays, "days", "d", 365, "Days until the key expires")
+}
+
+func formatKeyTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02 15:04")
+}
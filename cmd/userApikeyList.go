@@ -0,0 +1,62 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// userApikeyListCmd represents the apikey list command
+var userApikeyListCmd = &cobra.Command{
+	Use:   "list <email>",
+	Short: "List a user's API keys",
+	Long: `List a user's API keys.
+
+Shows the key ids, names, scopes, expiry and last use.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email := args[0]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		user, err := postgres.NewSubmitterModel(db).Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
+		}
+
+		keys, err := (&postgres.ApiKeyModel{DB: db}).List(user.Id)
+		if err != nil {
+			panic(fmt.Errorf("Error listing API keys: %s", err))
+		}
+
+		fmt.Printf("ID\tName\tScopes\tExpires\tLast used\n")
+		for _, key := range keys {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", key.Id, key.Name, strings.Join(key.Scopes, ", "),
+				formatKeyTime(key.Expires), formatKeyTime(key.LastUsed))
+		}
+	},
+}
+
+func init() {
+	userApikeyCmd.AddCommand(userApikeyListCmd)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// userApikeyRevokeCmd represents the apikey revoke command
+var userApikeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <email> <key id>",
+	Short: "Revoke a user's API key",
+	Long: `Revoke a user's API key.
+
+The key stops working immediately.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		email := args[0]
+		keyId := args[1]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		user, err := postgres.NewSubmitterModel(db).Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
+		}
+
+		err = (&postgres.ApiKeyModel{DB: db}).Revoke(user.Id, keyId)
+		if err != nil {
+			panic(fmt.Errorf("Error revoking API key %s: %s", keyId, err))
+		}
+
+		recordAudit(db, models.AuditApiKeyRevoke, user.Id, map[string]interface{}{"id": keyId}, nil)
+	},
+}
+
+func init() {
+	userApikeyCmd.AddCommand(userApikeyRevokeCmd)
+}
@@ -20,16 +20,21 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"secondarymetabolites.org/mibig-api/pkg/models"
 	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
 )
 
+var deleteAnonymise bool
+
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
 	Use:   "delete <email>",
 	Short: "Delete a user for the MIBiG API server",
 	Long: `Delete a user for the MIBiG API server.
 
-Also cleans up group memberships of the deleted user.`,
+Also cleans up group memberships of the deleted user.
+With --anonymise, the user's personal data is erased instead, keeping the
+user id so changelog entries referring to it stay valid.`,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		email := args[0]
@@ -39,13 +44,35 @@ Also cleans up group memberships of the deleted user.`,
 		}
 
 		userModel := postgres.NewSubmitterModel(db)
+
+		if deleteAnonymise {
+			user, err := userModel.Get(email, false)
+			if err != nil {
+				panic(fmt.Errorf("Error getting user: %s", err))
+			}
+			err = userModel.Anonymise(user.Id)
+			if err != nil {
+				panic(fmt.Errorf("Error anonymising user: %s", err))
+			}
+			recordAudit(db, models.AuditUserAnonymise, user.Id, nil, nil)
+			return
+		}
+
+		user, err := userModel.Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error getting user: %s", err))
+		}
+
 		err = userModel.Delete(email)
 		if err != nil {
 			panic(fmt.Errorf("Error deleting user: %s", err))
 		}
+		recordAudit(db, models.AuditUserDelete, user.Id, nil, nil)
 	},
 }
 
 func init() {
 	userCmd.AddCommand(deleteCmd)
+
+	deleteCmd.Flags().BoolVar(&deleteAnonymise, "anonymise", false, "Erase the user's personal data but keep the user id")
 }
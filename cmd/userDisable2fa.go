@@ -0,0 +1,68 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// userDisable2faCmd represents the disable-2fa command
+var userDisable2faCmd = &cobra.Command{
+	Use:   "disable-2fa <email>",
+	Short: "Disable two-factor authentication for a user",
+	Long: `Disable two-factor authentication for a user.
+
+Use this if a user lost both their authenticator app and their recovery codes.
+The user's sessions are revoked, so they need to log in again.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email := args[0]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		user, err := postgres.NewSubmitterModel(db).Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
+		}
+
+		twoFactorModel := postgres.TwoFactorModel{DB: db}
+		err = twoFactorModel.Disable(user.Id)
+		if err != nil {
+			panic(fmt.Errorf("Error disabling two-factor authentication: %s", err))
+		}
+
+		sessionModel := postgres.SessionModel{DB: db}
+		err = sessionModel.RevokeAll(user.Id)
+		if err != nil {
+			panic(fmt.Errorf("Error revoking sessions: %s", err))
+		}
+
+		recordAudit(db, models.AuditTwoFactorDisable, user.Id, nil, nil)
+
+		fmt.Printf("Disabled two-factor authentication for %s\n", email)
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userDisable2faCmd)
+}
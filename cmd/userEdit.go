@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"secondarymetabolites.org/mibig-api/pkg/models"
 	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
 )
 
@@ -46,11 +47,17 @@ Change the user email, name, password, or roles.`,
 			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
 		}
 
+		before := user.Audit()
 		password := InteractiveUserEdit(user, &roleModel, submitterModel)
 		err = submitterModel.Update(user, password)
 		if err != nil {
 			panic(fmt.Errorf("Error updating user: %s", err))
 		}
+
+		recordAudit(db, models.AuditUserUpdate, user.Id, before, user.Audit())
+		if password != "" {
+			recordAudit(db, models.AuditUserPassword, user.Id, nil, nil)
+		}
 	},
 }
 
@@ -48,6 +48,7 @@ Only roles the user possesses will be removed.`,
 			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
 		}
 
+		before := user.Audit()
 		oldRoleNames := models.RolesToStrings(user.Roles)
 
 		roleNames := utils.DifferenceString(oldRoleNames, deleteRoleNames)
@@ -61,6 +62,8 @@ Only roles the user possesses will be removed.`,
 		if err != nil {
 			panic(fmt.Errorf("Error updating user: %s", err))
 		}
+
+		recordAudit(db, models.AuditUserRoles, user.Id, before, user.Audit())
 	},
 }
 
@@ -0,0 +1,71 @@
+/*
+Copyright © 2020 Kai Blin <kblin@biosustain.dtu.dk>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
+)
+
+// userUnlockCmd represents the unlock command
+var userUnlockCmd = &cobra.Command{
+	Use:   "unlock <email>",
+	Short: "Unlock a user locked out after failed logins",
+	Long: `Unlock a user locked out after failed logins.
+
+Also resets the user's count of failed logins.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		email := args[0]
+		db, err := InitDb()
+		if err != nil {
+			panic(fmt.Errorf("Error opening database: %s", err))
+		}
+
+		user, err := postgres.NewSubmitterModel(db).Get(email, false)
+		if err != nil {
+			panic(fmt.Errorf("Error reading user for %s: %s", email, err))
+		}
+
+		lockoutModel := postgres.LockoutModel{DB: db}
+		lockout, err := lockoutModel.Get(user.Id)
+		if err != nil {
+			panic(fmt.Errorf("Error reading lockout for %s: %s", email, err))
+		}
+
+		err = lockoutModel.Reset(user.Id)
+		if err != nil {
+			panic(fmt.Errorf("Error unlocking user: %s", err))
+		}
+
+		recordAudit(db, models.AuditUserUnlock, user.Id, lockout, nil)
+
+		if lockout.Locked(time.Now()) {
+			fmt.Printf("Unlocked %s, was locked until %s\n", email, lockout.LockedUntil.Format("2006-01-02 15:04"))
+		} else {
+			fmt.Printf("%s was not locked, reset %d failed logins\n", email, lockout.Failures)
+		}
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userUnlockCmd)
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// CompoundContentTypes maps the supported compound export formats to their MIME types
+var CompoundContentTypes = map[string]string{
+	"smiles": "text/tab-separated-values",
+	"sdf":    "chemical/x-mdl-sdfile",
+}
+
+// CompoundRecord is a single compound structure together with the entry it belongs to
+type CompoundRecord struct {
+	Accession string
+	Compound  models.Compound
+}
+
+// CompoundRecords collects all compounds with a known structure from the given entries
+func CompoundRecords(entries []models.Entry) ([]CompoundRecord, error) {
+	var records []CompoundRecord
+	for i := range entries {
+		data, err := entries[i].Details()
+		if err != nil {
+			return nil, err
+		}
+		for _, compound := range data.Cluster.Compounds {
+			if compound.Structure == "" {
+				continue
+			}
+			records = append(records, CompoundRecord{Accession: entries[i].Acc, Compound: compound})
+		}
+	}
+	return records, nil
+}
+
+// WriteCompounds serialises the compound records in the requested format, "smiles" or "sdf"
+func WriteCompounds(w io.Writer, records []CompoundRecord, format string) error {
+	switch format {
+	case "smiles":
+		return WriteSmilesTSV(w, records)
+	case "sdf":
+		return WriteSDF(w, records)
+	}
+	return ErrUnknownFormat
+}
+
+// WriteSmilesTSV writes one compound per line, SMILES first so the file can be read as a .smi file
+func WriteSmilesTSV(w io.Writer, records []CompoundRecord) error {
+	out := bufio.NewWriter(w)
+	out.WriteString("smiles\taccession\tname\tformula\tmass\tactivities\tdatabase_ids\n")
+	for _, record := range records {
+		fields := []string{
+			record.Compound.Structure,
+			record.Accession,
+			record.Compound.Name,
+			record.Compound.Formula,
+			formatMass(record.Compound.Mass),
+			strings.Join(record.Compound.Activities, ";"),
+			strings.Join(record.Compound.DatabaseIds, ";"),
+		}
+		for i := range fields {
+			fields[i] = sanitiseField(fields[i])
+		}
+		out.WriteString(strings.Join(fields, "\t"))
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+// WriteSDF writes an SD file with one record per compound.
+//
+// MIBiG only stores SMILES, so every record carries an empty connection table and the
+// structure in the SMILES data item, for tools to generate coordinates from.
+func WriteSDF(w io.Writer, records []CompoundRecord) error {
+	out := bufio.NewWriter(w)
+	for _, record := range records {
+		fmt.Fprintf(out, "%s\n  mibig-api\n%s\n", sanitiseField(record.Compound.Name), record.Accession)
+		out.WriteString("  0  0  0  0  0  0  0  0  0  0999 V2000\nM  END\n")
+
+		writeSDFProperty(out, "SMILES", record.Compound.Structure)
+		writeSDFProperty(out, "MIBIG_ACCESSION", record.Accession)
+		writeSDFProperty(out, "NAME", record.Compound.Name)
+		writeSDFProperty(out, "FORMULA", record.Compound.Formula)
+		writeSDFProperty(out, "MASS", formatMass(record.Compound.Mass))
+		writeSDFProperty(out, "ACTIVITIES", strings.Join(record.Compound.Activities, "; "))
+		writeSDFProperty(out, "DATABASE_IDS", strings.Join(record.Compound.DatabaseIds, "; "))
+
+		out.WriteString("$$$$\n")
+	}
+	return out.Flush()
+}
+
+func writeSDFProperty(out *bufio.Writer, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(out, "> <%s>\n%s\n\n", name, sanitiseField(value))
+}
+
+func formatMass(mass float64) string {
+	if mass <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(mass, 'f', -1, 64)
+}
+
+var fieldSanitiser = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func sanitiseField(value string) string {
+	return fieldSanitiser.Replace(value)
+}
@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreyvit/diff"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestCompoundRecords(t *testing.T) {
+	entry := newTestEntry()
+	cluster := entry.Data["cluster"].(map[string]interface{})
+	cluster["compounds"] = append(cluster["compounds"].([]interface{}), map[string]interface{}{"compound": "no structure"})
+
+	records, err := CompoundRecords([]models.Entry{*entry})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	if records[0].Accession != "BGC0000535" || records[0].Compound.Name != "nisin A" {
+		t.Errorf("Unexpected record %v", records[0])
+	}
+}
+
+func TestWriteSmilesTSV(t *testing.T) {
+	records, err := CompoundRecords([]models.Entry{*newTestEntry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err = WriteCompounds(&out, records, "smiles"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "smiles\taccession\tname\tformula\tmass\tactivities\tdatabase_ids\n" +
+		"CCC(C)C1C(=O)NC(=C)C(=O)N1\tBGC0000535\tnisin A\tC179N62O37S7\t3834.7822\tAntibacterial;Signalling\tpubchem:16130280;chemspider:4883396\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected TSV output:\n%s", diff.LineDiff(expected, out.String()))
+	}
+}
+
+func TestWriteSDF(t *testing.T) {
+	records, err := CompoundRecords([]models.Entry{*newTestEntry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err = WriteCompounds(&out, records, "sdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `nisin A
+  mibig-api
+BGC0000535
+  0  0  0  0  0  0  0  0  0  0999 V2000
+M  END
+> <SMILES>
+CCC(C)C1C(=O)NC(=C)C(=O)N1
+
+> <MIBIG_ACCESSION>
+BGC0000535
+
+> <NAME>
+nisin A
+
+> <FORMULA>
+C179N62O37S7
+
+> <MASS>
+3834.7822
+
+> <ACTIVITIES>
+Antibacterial; Signalling
+
+> <DATABASE_IDS>
+pubchem:16130280; chemspider:4883396
+
+$$$$
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected SDF output:\n%s", diff.LineDiff(expected, out.String()))
+	}
+
+	if err = WriteCompounds(&out, records, "mol2"); err != ErrUnknownFormat {
+		t.Errorf("Expected %v, got %v", ErrUnknownFormat, err)
+	}
+}
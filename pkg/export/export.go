@@ -0,0 +1,69 @@
+// Package export converts MIBiG entries into formats used by external tools.
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+const BaseURL = "https://mibig.secondarymetabolites.org"
+
+// EntryIRI returns the stable IRI of a MIBiG entry
+func EntryIRI(accession string) string {
+	return fmt.Sprintf("%s/repository/%s/", BaseURL, escapeIRI(accession))
+}
+
+// CompoundIRI returns the stable IRI of the index-th compound of a MIBiG entry
+func CompoundIRI(accession string, index int) string {
+	return fmt.Sprintf("%s#compound_%d", EntryIRI(accession), index+1)
+}
+
+// TaxonIRI returns the NCBI taxonomy IRI for a tax id
+func TaxonIRI(taxId int) string {
+	return fmt.Sprintf("http://purl.obolibrary.org/obo/NCBITaxon_%d", taxId)
+}
+
+// TypeIRI returns the IRI of a MIBiG biosynthetic class
+func TypeIRI(class string) string {
+	return fmt.Sprintf("%s/type/%s", BaseURL, escapeIRI(strings.Replace(class, " ", "_", -1)))
+}
+
+var crossReferencePrefixes = map[string]string{
+	"pubchem":    "https://identifiers.org/pubchem.compound:",
+	"chemspider": "https://identifiers.org/chemspider:",
+	"chebi":      "https://identifiers.org/CHEBI:",
+	"chembl":     "https://identifiers.org/chembl.compound:",
+	"npatlas":    "https://identifiers.org/npatlas:",
+	"pubmed":     "https://identifiers.org/pubmed:",
+	"doi":        "https://doi.org/",
+}
+
+// CrossReferenceURL turns a "database:id" style identifier into a resolvable URL
+func CrossReferenceURL(identifier string) (string, bool) {
+	parts := strings.SplitN(identifier, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", false
+	}
+
+	prefix, ok := crossReferencePrefixes[strings.ToLower(parts[0])]
+	if !ok {
+		return "", false
+	}
+
+	return prefix + escapeIRI(parts[1]), true
+}
+
+// escapeIRI percent-encodes the characters that may not appear in an IRI,
+// so values taken from entry data can't break the serialised IRI
+func escapeIRI(component string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(component); i++ {
+		b := component[i]
+		if b <= ' ' || b == 0x7f || strings.IndexByte(`<>"{}|\^%`+"`", b) >= 0 {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+			continue
+		}
+		escaped.WriteByte(b)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"testing"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func newTestEntry() *models.Entry {
+	return &models.Entry{
+		ID:    535,
+		Acc:   "BGC0000535",
+		TaxID: 1360,
+		Data: models.JsonData{
+			"cluster": map[string]interface{}{
+				"mibig_accession": "BGC0000535",
+				"organism_name":   "Lactococcus lactis subsp. lactis",
+				"ncbi_tax_id":     "1360",
+				"biosyn_class":    []interface{}{"RiPP"},
+				"minimal":         false,
+				"loci": map[string]interface{}{
+					"accession":    "HM219853.1",
+					"completeness": "complete",
+				},
+				"compounds": []interface{}{
+					map[string]interface{}{
+						"compound":          "nisin A",
+						"chem_struct":       "CCC(C)C1C(=O)NC(=C)C(=O)N1",
+						"molecular_formula": "C179N62O37S7",
+						"mol_mass":          3834.7822,
+						"chem_acts":         []interface{}{"Antibacterial", "Signalling"},
+						"database_id":       []interface{}{"pubchem:16130280", "chemspider:4883396"},
+						"chem_synonyms":     []interface{}{"nisin"},
+					},
+				},
+				"publications": []interface{}{"pubmed:21183019"},
+			},
+			"changelog": []interface{}{
+				map[string]interface{}{
+					"version":  "2.0",
+					"comments": []interface{}{"Migrated from v1.4"},
+				},
+			},
+		},
+		Taxon: &models.Taxon{
+			TaxID:        1360,
+			Superkingdom: "Bacteria",
+			Phylum:       "Firmicutes",
+			Class:        "Bacilli",
+			Order:        "Lactobacillales",
+			Family:       "Streptococcaceae",
+			Genus:        "Lactococcus",
+			Species:      "lactis",
+			Name:         "Lactococcus lactis subsp. lactis",
+		},
+	}
+}
+
+func TestCrossReferenceURL(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Identifier string
+		Expected   string
+		Ok         bool
+	}{
+		{Name: "pubchem", Identifier: "pubchem:16130280", Expected: "https://identifiers.org/pubchem.compound:16130280", Ok: true},
+		{Name: "chemspider", Identifier: "chemspider:4883396", Expected: "https://identifiers.org/chemspider:4883396", Ok: true},
+		{Name: "pubmed", Identifier: "pubmed:21183019", Expected: "https://identifiers.org/pubmed:21183019", Ok: true},
+		{Name: "doi", Identifier: "doi:10.1000/182", Expected: "https://doi.org/10.1000/182", Ok: true},
+		{Name: "unknown", Identifier: "foo:bar", Expected: "", Ok: false},
+		{Name: "no prefix", Identifier: "16130280", Expected: "", Ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			url, ok := CrossReferenceURL(tt.Identifier)
+			if ok != tt.Ok {
+				t.Errorf("CrossReferenceURL(%s): expected ok %t, got %t", tt.Identifier, tt.Ok, ok)
+			}
+			if url != tt.Expected {
+				t.Errorf("CrossReferenceURL(%s): expected %s, got %s", tt.Identifier, tt.Expected, url)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const (
+	geneProfile            = "https://bioschemas.org/profiles/Gene/1.0-RELEASE"
+	taxonProfile           = "https://bioschemas.org/profiles/Taxon/1.0-RELEASE"
+	molecularEntityProfile = "https://bioschemas.org/profiles/MolecularEntity/1.0-RELEASE"
+)
+
+// JsonLD is a JSON-LD document, ready to be serialised
+type JsonLD map[string]interface{}
+
+var jsonLDContext = map[string]string{
+	"@vocab": "https://schema.org/",
+	"dct":    "http://purl.org/dc/terms/",
+}
+
+// EntryToJsonLD builds a Bioschemas-compliant JSON-LD representation of an entry
+func EntryToJsonLD(entry *models.Entry) (JsonLD, error) {
+	data, err := entry.Details()
+	if err != nil {
+		return nil, err
+	}
+
+	iri := EntryIRI(entry.Acc)
+
+	doc := JsonLD{
+		"@context":             jsonLDContext,
+		"@id":                  iri,
+		"@type":                "Gene",
+		"dct:conformsTo":       JsonLD{"@id": geneProfile},
+		"identifier":           entry.Acc,
+		"name":                 entryName(entry.Acc, data),
+		"url":                  iri,
+		"description":          entryDescription(data),
+		"isPartOf":             JsonLD{"@type": "Dataset", "@id": BaseURL + "/", "name": "MIBiG"},
+		"additionalType":       data.Cluster.BiosynClass,
+		"taxonomicRange":       taxonToJsonLD(entry.TaxID, entry.Taxon),
+		"encodesBioChemEntity": compoundsToJsonLD(entry.Acc, data.Cluster.Compounds),
+		"citation":             publicationsToJsonLD(data.Cluster.Publications),
+	}
+
+	if data.Cluster.Loci.Accession != "" {
+		doc["sameAs"] = "https://www.ncbi.nlm.nih.gov/nuccore/" + data.Cluster.Loci.Accession
+	}
+
+	return doc, nil
+}
+
+func entryName(accession string, data *models.EntryData) string {
+	var names []string
+	for _, compound := range data.Cluster.Compounds {
+		names = append(names, compound.Name)
+	}
+	if len(names) == 0 {
+		return accession
+	}
+	return fmt.Sprintf("%s biosynthetic gene cluster", strings.Join(names, " / "))
+}
+
+func entryDescription(data *models.EntryData) string {
+	classes := strings.Join(data.Cluster.BiosynClass, " / ")
+	if data.Cluster.OrganismName == "" {
+		return fmt.Sprintf("%s biosynthetic gene cluster", classes)
+	}
+	return fmt.Sprintf("%s biosynthetic gene cluster from %s", classes, data.Cluster.OrganismName)
+}
+
+func taxonToJsonLD(taxId int, taxon *models.Taxon) JsonLD {
+	leaf := JsonLD{
+		"@type":          "Taxon",
+		"@id":            TaxonIRI(taxId),
+		"dct:conformsTo": JsonLD{"@id": taxonProfile},
+		"identifier":     taxId,
+	}
+	if taxon == nil {
+		return leaf
+	}
+	leaf["name"] = taxon.Name
+
+	// Walk the lineage from the most specific rank up, nesting each level as parentTaxon
+	lineage := []struct{ rank, name string }{
+		{"species", taxon.RankValue("species")},
+		{"genus", taxon.Genus},
+		{"family", taxon.Family},
+		{"order", taxon.Order},
+		{"class", taxon.Class},
+		{"phylum", taxon.Phylum},
+		{"kingdom", taxon.Kingdom},
+		{"superkingdom", taxon.Superkingdom},
+	}
+
+	current := leaf
+	for _, level := range lineage {
+		if level.name == "" {
+			continue
+		}
+		if level.name == taxon.Name {
+			current["taxonRank"] = level.rank
+			continue
+		}
+		parent := JsonLD{
+			"@type":     "Taxon",
+			"name":      level.name,
+			"taxonRank": level.rank,
+		}
+		current["parentTaxon"] = parent
+		current = parent
+	}
+
+	return leaf
+}
+
+func compoundsToJsonLD(accession string, compounds models.CompoundList) []JsonLD {
+	entities := make([]JsonLD, 0, len(compounds))
+	for i, compound := range compounds {
+		entity := JsonLD{
+			"@type":          "MolecularEntity",
+			"@id":            CompoundIRI(accession, i),
+			"dct:conformsTo": JsonLD{"@id": molecularEntityProfile},
+			"identifier":     fmt.Sprintf("%s.%d", accession, i+1),
+			"name":           compound.Name,
+		}
+		if compound.Structure != "" {
+			entity["smiles"] = compound.Structure
+		}
+		if compound.Formula != "" {
+			entity["molecularFormula"] = compound.Formula
+		}
+		if compound.Mass > 0 {
+			entity["molecularWeight"] = compound.Mass
+		}
+		if len(compound.Synonyms) > 0 {
+			entity["alternateName"] = compound.Synonyms
+		}
+
+		var sameAs []string
+		for _, id := range compound.DatabaseIds {
+			if url, ok := CrossReferenceURL(id); ok {
+				sameAs = append(sameAs, url)
+			}
+		}
+		if len(sameAs) > 0 {
+			entity["sameAs"] = sameAs
+		}
+
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+func publicationsToJsonLD(publications []string) []JsonLD {
+	articles := make([]JsonLD, 0, len(publications))
+	for _, publication := range publications {
+		article := JsonLD{
+			"@type":      "ScholarlyArticle",
+			"identifier": publication,
+		}
+		if url, ok := CrossReferenceURL(publication); ok {
+			article["@id"] = url
+			article["url"] = url
+		}
+		articles = append(articles, article)
+	}
+	return articles
+}
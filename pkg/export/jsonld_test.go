@@ -0,0 +1,92 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEntryToJsonLD(t *testing.T) {
+	doc, err := EntryToJsonLD(newTestEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed map[string]interface{}
+	if err = json.Unmarshal(raw, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := parsed["@context"]; !ok {
+		t.Errorf("Missing @context")
+	}
+
+	if parsed["@id"] != "https://mibig.secondarymetabolites.org/repository/BGC0000535/" {
+		t.Errorf("Unexpected @id %v", parsed["@id"])
+	}
+
+	if parsed["@type"] != "Gene" {
+		t.Errorf("Unexpected @type %v", parsed["@type"])
+	}
+
+	taxon := parsed["taxonomicRange"].(map[string]interface{})
+	if taxon["@id"] != "http://purl.obolibrary.org/obo/NCBITaxon_1360" {
+		t.Errorf("Unexpected taxon @id %v", taxon["@id"])
+	}
+
+	var lineage []string
+	for current := taxon; current != nil; {
+		lineage = append(lineage, current["name"].(string))
+		parent, ok := current["parentTaxon"].(map[string]interface{})
+		if !ok {
+			break
+		}
+		current = parent
+	}
+	expectedLineage := []string{
+		"Lactococcus lactis subsp. lactis", "Lactococcus lactis", "Lactococcus", "Streptococcaceae",
+		"Lactobacillales", "Bacilli", "Firmicutes", "Bacteria",
+	}
+	if !cmp.Equal(expectedLineage, lineage) {
+		t.Errorf("Unexpected lineage:\n%s", cmp.Diff(expectedLineage, lineage))
+	}
+
+	compounds := parsed["encodesBioChemEntity"].([]interface{})
+	if len(compounds) != 1 {
+		t.Fatalf("Expected 1 compound, got %d", len(compounds))
+	}
+	compound := compounds[0].(map[string]interface{})
+	expectedCompound := map[string]interface{}{
+		"@type":            "MolecularEntity",
+		"@id":              "https://mibig.secondarymetabolites.org/repository/BGC0000535/#compound_1",
+		"dct:conformsTo":   map[string]interface{}{"@id": molecularEntityProfile},
+		"identifier":       "BGC0000535.1",
+		"name":             "nisin A",
+		"smiles":           "CCC(C)C1C(=O)NC(=C)C(=O)N1",
+		"molecularFormula": "C179N62O37S7",
+		"molecularWeight":  3834.7822,
+		"alternateName":    []interface{}{"nisin"},
+		"sameAs": []interface{}{
+			"https://identifiers.org/pubchem.compound:16130280",
+			"https://identifiers.org/chemspider:4883396",
+		},
+	}
+	if !cmp.Equal(expectedCompound, compound) {
+		t.Errorf("Unexpected compound:\n%s", cmp.Diff(expectedCompound, compound))
+	}
+
+	citations := parsed["citation"].([]interface{})
+	if len(citations) != 1 {
+		t.Fatalf("Expected 1 citation, got %d", len(citations))
+	}
+	citation := citations[0].(map[string]interface{})
+	if citation["@type"] != "ScholarlyArticle" || citation["@id"] != "https://identifiers.org/pubmed:21183019" {
+		t.Errorf("Unexpected citation %v", citation)
+	}
+}
@@ -0,0 +1,322 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const (
+	rdfType       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	rdfsLabel     = "http://www.w3.org/2000/01/rdf-schema#label"
+	xsdDouble     = "http://www.w3.org/2001/XMLSchema#double"
+	xsdInteger    = "http://www.w3.org/2001/XMLSchema#integer"
+	xsdBoolean    = "http://www.w3.org/2001/XMLSchema#boolean"
+	schemaOrg     = "https://schema.org/"
+	dctIsPartOf   = "http://purl.org/dc/terms/isPartOf"
+	skosConcept   = "http://www.w3.org/2004/02/skos/core#Concept"
+	skosPrefLabel = "http://www.w3.org/2004/02/skos/core#prefLabel"
+	mibigVocab    = BaseURL + "/ontology#"
+)
+
+var rdfPrefixes = []struct{ Prefix, IRI string }{
+	{"rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#"},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+	{"xsd", "http://www.w3.org/2001/XMLSchema#"},
+	{"schema", schemaOrg},
+	{"dct", "http://purl.org/dc/terms/"},
+	{"skos", "http://www.w3.org/2004/02/skos/core#"},
+	{"mibig", mibigVocab},
+	{"ncbitaxon", "http://purl.obolibrary.org/obo/NCBITaxon_"},
+}
+
+type termKind int
+
+const (
+	iriTerm termKind = iota
+	literalTerm
+)
+
+// Term is an RDF node, either an IRI or a (possibly typed) literal
+type Term struct {
+	kind     termKind
+	Value    string
+	Datatype string
+}
+
+func IRI(value string) Term {
+	return Term{kind: iriTerm, Value: value}
+}
+
+func Literal(value string) Term {
+	return Term{kind: literalTerm, Value: value}
+}
+
+func TypedLiteral(value, datatype string) Term {
+	return Term{kind: literalTerm, Value: value, Datatype: datatype}
+}
+
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// Graph is an ordered, duplicate-free collection of triples
+type Graph struct {
+	Triples []Triple
+	seen    map[Triple]bool
+}
+
+func NewGraph() *Graph {
+	return &Graph{seen: make(map[Triple]bool)}
+}
+
+func (g *Graph) Add(subject, predicate string, object Term) {
+	triple := Triple{Subject: IRI(subject), Predicate: IRI(predicate), Object: object}
+	if g.seen[triple] {
+		return
+	}
+	g.seen[triple] = true
+	g.Triples = append(g.Triples, triple)
+}
+
+// EntriesToGraph converts MIBiG entries with their compounds, taxa, publications and
+// biosynthetic types into an RDF graph
+func EntriesToGraph(entries []models.Entry) (*Graph, error) {
+	g := NewGraph()
+	dataset := BaseURL + "/"
+	g.Add(dataset, rdfType, IRI(schemaOrg+"Dataset"))
+	g.Add(dataset, schemaOrg+"name", Literal("MIBiG"))
+
+	for i := range entries {
+		if err := addEntry(g, &entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func addEntry(g *Graph, entry *models.Entry) error {
+	data, err := entry.Details()
+	if err != nil {
+		return err
+	}
+
+	iri := EntryIRI(entry.Acc)
+	g.Add(iri, rdfType, IRI(schemaOrg+"Gene"))
+	g.Add(iri, schemaOrg+"identifier", Literal(entry.Acc))
+	g.Add(iri, rdfsLabel, Literal(entryName(entry.Acc, data)))
+	g.Add(iri, dctIsPartOf, IRI(BaseURL+"/"))
+	g.Add(iri, mibigVocab+"minimal", TypedLiteral(strconv.FormatBool(data.Cluster.Minimal), xsdBoolean))
+	if data.Cluster.Loci.Completeness != "" {
+		g.Add(iri, mibigVocab+"completeness", Literal(data.Cluster.Loci.Completeness))
+	}
+	if data.Cluster.Loci.Accession != "" {
+		g.Add(iri, schemaOrg+"sameAs", IRI("https://www.ncbi.nlm.nih.gov/nuccore/"+escapeIRI(data.Cluster.Loci.Accession)))
+	}
+
+	for _, class := range data.Cluster.BiosynClass {
+		typeIRI := TypeIRI(class)
+		g.Add(iri, mibigVocab+"biosyntheticClass", IRI(typeIRI))
+		g.Add(typeIRI, rdfType, IRI(skosConcept))
+		g.Add(typeIRI, skosPrefLabel, Literal(class))
+	}
+
+	if entry.TaxID > 0 {
+		taxonIRI := TaxonIRI(entry.TaxID)
+		g.Add(iri, schemaOrg+"taxonomicRange", IRI(taxonIRI))
+		g.Add(taxonIRI, rdfType, IRI(schemaOrg+"Taxon"))
+		g.Add(taxonIRI, schemaOrg+"identifier", TypedLiteral(strconv.Itoa(entry.TaxID), xsdInteger))
+		if entry.Taxon != nil && entry.Taxon.Name != "" {
+			g.Add(taxonIRI, schemaOrg+"name", Literal(entry.Taxon.Name))
+		}
+	}
+
+	for _, publication := range data.Cluster.Publications {
+		pubIRI, ok := CrossReferenceURL(publication)
+		if !ok {
+			g.Add(iri, schemaOrg+"citation", Literal(publication))
+			continue
+		}
+		g.Add(iri, schemaOrg+"citation", IRI(pubIRI))
+		g.Add(pubIRI, rdfType, IRI(schemaOrg+"ScholarlyArticle"))
+		g.Add(pubIRI, schemaOrg+"identifier", Literal(publication))
+	}
+
+	for i, compound := range data.Cluster.Compounds {
+		compoundIRI := CompoundIRI(entry.Acc, i)
+		g.Add(iri, schemaOrg+"encodesBioChemEntity", IRI(compoundIRI))
+		g.Add(compoundIRI, rdfType, IRI(schemaOrg+"MolecularEntity"))
+		g.Add(compoundIRI, schemaOrg+"name", Literal(compound.Name))
+		for _, synonym := range compound.Synonyms {
+			g.Add(compoundIRI, schemaOrg+"alternateName", Literal(synonym))
+		}
+		if compound.Structure != "" {
+			g.Add(compoundIRI, schemaOrg+"smiles", Literal(compound.Structure))
+		}
+		if compound.Formula != "" {
+			g.Add(compoundIRI, schemaOrg+"molecularFormula", Literal(compound.Formula))
+		}
+		if compound.Mass > 0 {
+			g.Add(compoundIRI, schemaOrg+"molecularWeight", TypedLiteral(strconv.FormatFloat(compound.Mass, 'f', -1, 64), xsdDouble))
+		}
+		for _, activity := range compound.Activities {
+			g.Add(compoundIRI, mibigVocab+"activity", Literal(activity))
+		}
+		for _, id := range compound.DatabaseIds {
+			if url, ok := CrossReferenceURL(id); ok {
+				g.Add(compoundIRI, schemaOrg+"sameAs", IRI(url))
+			} else {
+				g.Add(compoundIRI, schemaOrg+"identifier", Literal(id))
+			}
+		}
+	}
+
+	return nil
+}
+
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+// RDFContentTypes maps the supported RDF serialisations to their MIME types
+var RDFContentTypes = map[string]string{
+	"turtle":   "text/turtle",
+	"ntriples": "application/n-triples",
+}
+
+// WriteRDF serialises the graph in the requested format, "turtle" or "ntriples"
+func WriteRDF(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case "turtle":
+		return WriteTurtle(w, g)
+	case "ntriples":
+		return WriteNTriples(w, g)
+	}
+	return ErrUnknownFormat
+}
+
+// WriteNTriples serialises the graph as N-Triples
+func WriteNTriples(w io.Writer, g *Graph) error {
+	out := bufio.NewWriter(w)
+	for _, triple := range g.Triples {
+		fmt.Fprintf(out, "%s %s %s .\n", ntriplesTerm(triple.Subject), ntriplesTerm(triple.Predicate), ntriplesTerm(triple.Object))
+	}
+	return out.Flush()
+}
+
+// WriteTurtle serialises the graph as Turtle, grouping triples by subject
+func WriteTurtle(w io.Writer, g *Graph) error {
+	out := bufio.NewWriter(w)
+	for _, prefix := range rdfPrefixes {
+		fmt.Fprintf(out, "@prefix %s: <%s> .\n", prefix.Prefix, prefix.IRI)
+	}
+
+	var subjects []string
+	bySubject := make(map[string][]Triple)
+	for _, triple := range g.Triples {
+		if _, ok := bySubject[triple.Subject.Value]; !ok {
+			subjects = append(subjects, triple.Subject.Value)
+		}
+		bySubject[triple.Subject.Value] = append(bySubject[triple.Subject.Value], triple)
+	}
+
+	for _, subject := range subjects {
+		fmt.Fprintf(out, "\n%s", turtleTerm(IRI(subject)))
+
+		var predicates []string
+		objects := make(map[string][]Term)
+		for _, triple := range bySubject[subject] {
+			if _, ok := objects[triple.Predicate.Value]; !ok {
+				predicates = append(predicates, triple.Predicate.Value)
+			}
+			objects[triple.Predicate.Value] = append(objects[triple.Predicate.Value], triple.Object)
+		}
+
+		for i, predicate := range predicates {
+			if i > 0 {
+				out.WriteString(" ;")
+			}
+			var rendered []string
+			for _, object := range objects[predicate] {
+				rendered = append(rendered, turtleTerm(object))
+			}
+			fmt.Fprintf(out, "\n    %s %s", turtlePredicate(predicate), strings.Join(rendered, ", "))
+		}
+		out.WriteString(" .\n")
+	}
+	return out.Flush()
+}
+
+func ntriplesTerm(t Term) string {
+	if t.kind == iriTerm {
+		return "<" + t.Value + ">"
+	}
+	literal := `"` + escapeLiteral(t.Value) + `"`
+	if t.Datatype != "" {
+		literal += "^^<" + t.Datatype + ">"
+	}
+	return literal
+}
+
+func turtlePredicate(predicate string) string {
+	if predicate == rdfType {
+		return "a"
+	}
+	return turtleTerm(IRI(predicate))
+}
+
+func turtleTerm(t Term) string {
+	if t.kind == iriTerm {
+		if compact, ok := compactIRI(t.Value); ok {
+			return compact
+		}
+		return "<" + t.Value + ">"
+	}
+	literal := `"` + escapeLiteral(t.Value) + `"`
+	if t.Datatype != "" {
+		literal += "^^" + turtleTerm(IRI(t.Datatype))
+	}
+	return literal
+}
+
+func compactIRI(iri string) (string, bool) {
+	// prefer the longest matching namespace
+	prefixes := make([]struct{ Prefix, IRI string }, len(rdfPrefixes))
+	copy(prefixes, rdfPrefixes)
+	sort.SliceStable(prefixes, func(i, j int) bool { return len(prefixes[i].IRI) > len(prefixes[j].IRI) })
+
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(iri, prefix.IRI) {
+			continue
+		}
+		local := strings.TrimPrefix(iri, prefix.IRI)
+		if isSimpleLocalName(local) {
+			return prefix.Prefix + ":" + local, true
+		}
+	}
+	return "", false
+}
+
+func isSimpleLocalName(local string) bool {
+	if local == "" {
+		return false
+	}
+	for _, r := range local {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func escapeLiteral(value string) string {
+	return literalEscaper.Replace(value)
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestEntriesToGraph(t *testing.T) {
+	g, err := EntriesToGraph([]models.Entry{*newTestEntry(), *newTestEntry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[Triple]bool)
+	for _, triple := range g.Triples {
+		if seen[triple] {
+			t.Errorf("Duplicate triple %v", triple)
+		}
+		seen[triple] = true
+	}
+
+	expected := []Triple{
+		{IRI(EntryIRI("BGC0000535")), IRI(rdfType), IRI(schemaOrg + "Gene")},
+		{IRI(EntryIRI("BGC0000535")), IRI(schemaOrg + "taxonomicRange"), IRI(TaxonIRI(1360))},
+		{IRI(EntryIRI("BGC0000535")), IRI(mibigVocab + "biosyntheticClass"), IRI(TypeIRI("RiPP"))},
+		{IRI(CompoundIRI("BGC0000535", 0)), IRI(schemaOrg + "sameAs"), IRI("https://identifiers.org/pubchem.compound:16130280")},
+		{IRI(CompoundIRI("BGC0000535", 0)), IRI(schemaOrg + "sameAs"), IRI("https://identifiers.org/chemspider:4883396")},
+		{IRI("https://identifiers.org/pubmed:21183019"), IRI(rdfType), IRI(schemaOrg + "ScholarlyArticle")},
+	}
+	for _, triple := range expected {
+		if !seen[triple] {
+			t.Errorf("Missing triple %v", triple)
+		}
+	}
+}
+
+func TestEntriesToGraphEscapesIRIs(t *testing.T) {
+	entry := newTestEntry()
+	cluster := entry.Data["cluster"].(map[string]interface{})
+	compound := cluster["compounds"].([]interface{})[0].(map[string]interface{})
+	compound["database_id"] = []interface{}{`pubchem:1 2>"3`}
+	cluster["publications"] = []interface{}{`doi:10.1000/a b>"c`}
+	cluster["loci"].(map[string]interface{})["accession"] = `HM 1>"`
+
+	g, err := EntriesToGraph([]models.Entry{*entry})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := WriteNTriples(&out, g); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"<https://identifiers.org/pubchem.compound:1%202%3E%223>",
+		"<https://doi.org/10.1000/a%20b%3E%22c>",
+		"<https://www.ncbi.nlm.nih.gov/nuccore/HM%201%3E%22>",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Missing escaped IRI %s in:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestWriteNTriples(t *testing.T) {
+	g := NewGraph()
+	g.Add("http://example.org/a", "http://example.org/name", Literal("say \"hi\"\n"))
+	g.Add("http://example.org/a", "http://example.org/mass", TypedLiteral("1.5", xsdDouble))
+
+	var out bytes.Buffer
+	if err := WriteNTriples(&out, g); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<http://example.org/a> <http://example.org/name> "say \"hi\"\n" .
+<http://example.org/a> <http://example.org/mass> "1.5"^^<http://www.w3.org/2001/XMLSchema#double> .
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected N-Triples output:\n%s", out.String())
+	}
+}
+
+func TestWriteTurtle(t *testing.T) {
+	g := NewGraph()
+	g.Add(EntryIRI("BGC0000535"), rdfType, IRI(schemaOrg+"Gene"))
+	g.Add(EntryIRI("BGC0000535"), schemaOrg+"citation", IRI("https://identifiers.org/pubmed:1"))
+	g.Add(EntryIRI("BGC0000535"), schemaOrg+"citation", IRI("https://identifiers.org/pubmed:2"))
+	g.Add(EntryIRI("BGC0000535"), schemaOrg+"taxonomicRange", IRI(TaxonIRI(1360)))
+
+	var out bytes.Buffer
+	if err := WriteTurtle(&out, g); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+<https://mibig.secondarymetabolites.org/repository/BGC0000535/>
+    a schema:Gene ;
+    schema:citation <https://identifiers.org/pubmed:1>, <https://identifiers.org/pubmed:2> ;
+    schema:taxonomicRange ncbitaxon:1360 .
+`
+	if !strings.HasPrefix(out.String(), "@prefix rdf: ") {
+		t.Errorf("Missing prefix declarations:\n%s", out.String())
+	}
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("Unexpected Turtle output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// ReferenceContentTypes maps the supported reference export formats to their MIME types
+var ReferenceContentTypes = map[string]string{
+	"bibtex": "application/x-bibtex",
+	"ris":    "application/x-research-info-systems",
+}
+
+// Reference is a publication cited by one or more MIBiG entries.
+// Publication is nil if the reference is missing from the local publication cache.
+type Reference struct {
+	Id          string
+	Publication *models.Publication
+	Accessions  []string
+}
+
+// CitedPublications returns the deduplicated publication ids cited by the entries,
+// mapped to the accessions citing them
+func CitedPublications(entries []models.Entry) (map[string][]string, error) {
+	cited := make(map[string][]string)
+	for i := range entries {
+		data, err := entries[i].Details()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range data.Cluster.Publications {
+			accessions := cited[id]
+			if len(accessions) > 0 && accessions[len(accessions)-1] == entries[i].Acc {
+				continue
+			}
+			cited[id] = append(accessions, entries[i].Acc)
+		}
+	}
+	return cited, nil
+}
+
+// BuildReferences combines the cited publications with the cached metadata, sorted by id
+func BuildReferences(cited map[string][]string, publications []models.Publication) []Reference {
+	byId := make(map[string]*models.Publication, len(publications))
+	for i := range publications {
+		byId[publications[i].Id] = &publications[i]
+	}
+
+	references := make([]Reference, 0, len(cited))
+	for id, accessions := range cited {
+		references = append(references, Reference{Id: id, Publication: byId[id], Accessions: accessions})
+	}
+	sort.Slice(references, func(i, j int) bool { return references[i].Id < references[j].Id })
+	return references
+}
+
+// WriteReferences serialises the references in the requested format, "bibtex" or "ris"
+func WriteReferences(w io.Writer, references []Reference, format string) error {
+	switch format {
+	case "bibtex":
+		return WriteBibTeX(w, references)
+	case "ris":
+		return WriteRIS(w, references)
+	}
+	return ErrUnknownFormat
+}
+
+func referenceNote(ref Reference) string {
+	return "MIBiG: " + strings.Join(ref.Accessions, ", ")
+}
+
+var bibtexKeyCleaner = strings.NewReplacer(":", "", "/", "_", ".", "_", " ", "")
+
+var bibtexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`,
+	"&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`)
+
+func WriteBibTeX(w io.Writer, references []Reference) error {
+	out := bufio.NewWriter(w)
+	for _, ref := range references {
+		key := bibtexKeyCleaner.Replace(ref.Id)
+		url, _ := CrossReferenceURL(ref.Id)
+
+		if ref.Publication == nil {
+			fmt.Fprintf(out, "@misc{%s,\n", key)
+			writeBibTeXField(out, "howpublished", ref.Id)
+			writeBibTeXField(out, "url", url)
+			writeBibTeXField(out, "note", referenceNote(ref))
+			out.WriteString("}\n\n")
+			continue
+		}
+
+		p := ref.Publication
+		fmt.Fprintf(out, "@article{%s,\n", key)
+		writeBibTeXField(out, "author", strings.Join(p.Authors, " and "))
+		writeBibTeXField(out, "title", p.Title)
+		writeBibTeXField(out, "journal", p.Journal)
+		if p.Year > 0 {
+			writeBibTeXField(out, "year", strconv.Itoa(p.Year))
+		}
+		writeBibTeXField(out, "volume", p.Volume)
+		writeBibTeXField(out, "number", p.Issue)
+		writeBibTeXField(out, "pages", strings.Replace(p.Pages, "-", "--", 1))
+		writeBibTeXField(out, "doi", p.Doi)
+		if strings.HasPrefix(ref.Id, "pubmed:") {
+			writeBibTeXField(out, "pmid", strings.TrimPrefix(ref.Id, "pubmed:"))
+		}
+		writeBibTeXField(out, "url", url)
+		writeBibTeXField(out, "note", referenceNote(ref))
+		out.WriteString("}\n\n")
+	}
+	return out.Flush()
+}
+
+func writeBibTeXField(out *bufio.Writer, name, value string) {
+	if value == "" {
+		return
+	}
+	if name != "url" && name != "doi" {
+		value = bibtexEscaper.Replace(value)
+	}
+	fmt.Fprintf(out, "  %s = {%s},\n", name, sanitiseField(value))
+}
+
+func WriteRIS(w io.Writer, references []Reference) error {
+	out := bufio.NewWriter(w)
+	for _, ref := range references {
+		url, _ := CrossReferenceURL(ref.Id)
+
+		if ref.Publication == nil {
+			writeRISField(out, "TY", "GEN")
+			writeRISField(out, "ID", ref.Id)
+			writeRISField(out, "UR", url)
+			writeRISField(out, "N1", referenceNote(ref))
+			writeRISField(out, "ER", "")
+			out.WriteString("\n")
+			continue
+		}
+
+		p := ref.Publication
+		writeRISField(out, "TY", "JOUR")
+		writeRISField(out, "ID", ref.Id)
+		for _, author := range p.Authors {
+			writeRISField(out, "AU", author)
+		}
+		writeRISField(out, "TI", p.Title)
+		writeRISField(out, "JO", p.Journal)
+		if p.Year > 0 {
+			writeRISField(out, "PY", strconv.Itoa(p.Year))
+		}
+		writeRISField(out, "VL", p.Volume)
+		writeRISField(out, "IS", p.Issue)
+		pages := strings.SplitN(p.Pages, "-", 2)
+		writeRISField(out, "SP", pages[0])
+		if len(pages) > 1 {
+			writeRISField(out, "EP", pages[1])
+		}
+		writeRISField(out, "DO", p.Doi)
+		writeRISField(out, "UR", url)
+		writeRISField(out, "N1", referenceNote(ref))
+		writeRISField(out, "ER", "")
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+func writeRISField(out *bufio.Writer, tag, value string) {
+	if value == "" && tag != "ER" {
+		return
+	}
+	fmt.Fprintf(out, "%s  - %s\n", tag, sanitiseField(value))
+}
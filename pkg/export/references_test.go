@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func newTestReferences(t *testing.T) []Reference {
+	first := newTestEntry()
+	second := newTestEntry()
+	second.Acc = "BGC0001070"
+	cluster := second.Data["cluster"].(map[string]interface{})
+	cluster["publications"] = []interface{}{"pubmed:21183019", "pubmed:4554808", "pubmed:21183019"}
+
+	cited, err := CitedPublications([]models.Entry{*first, *second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publications := []models.Publication{
+		{
+			Id:      "pubmed:21183019",
+			Title:   "Insights into nisin & friends",
+			Authors: []string{"User, Alice", "User, Bob"},
+			Journal: "Chem Biol",
+			Year:    2011,
+			Volume:  "18",
+			Issue:   "1",
+			Pages:   "90-100",
+			Doi:     "10.1016/j.chembiol.2010.11.010",
+		},
+	}
+
+	return BuildReferences(cited, publications)
+}
+
+func TestBuildReferences(t *testing.T) {
+	references := newTestReferences(t)
+
+	if len(references) != 2 {
+		t.Fatalf("Expected 2 references, got %d", len(references))
+	}
+
+	expected := [][]string{{"BGC0000535", "BGC0001070"}, {"BGC0001070"}}
+	actual := [][]string{references[0].Accessions, references[1].Accessions}
+	if !cmp.Equal(expected, actual) {
+		t.Errorf("Unexpected accessions:\n%s", cmp.Diff(expected, actual))
+	}
+
+	if references[1].Publication != nil {
+		t.Errorf("Expected uncached publication for %s", references[1].Id)
+	}
+}
+
+func TestWriteBibTeX(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteReferences(&out, newTestReferences(t), "bibtex"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `@article{pubmed21183019,
+  author = {User, Alice and User, Bob},
+  title = {Insights into nisin \& friends},
+  journal = {Chem Biol},
+  year = {2011},
+  volume = {18},
+  number = {1},
+  pages = {90--100},
+  doi = {10.1016/j.chembiol.2010.11.010},
+  pmid = {21183019},
+  url = {https://identifiers.org/pubmed:21183019},
+  note = {MIBiG: BGC0000535, BGC0001070},
+}
+
+@misc{pubmed4554808,
+  howpublished = {pubmed:4554808},
+  url = {https://identifiers.org/pubmed:4554808},
+  note = {MIBiG: BGC0001070},
+}
+
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected BibTeX output:\n%s", diff.LineDiff(expected, out.String()))
+	}
+}
+
+func TestWriteRIS(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteReferences(&out, newTestReferences(t), "ris"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `TY  - JOUR
+ID  - pubmed:21183019
+AU  - User, Alice
+AU  - User, Bob
+TI  - Insights into nisin & friends
+JO  - Chem Biol
+PY  - 2011
+VL  - 18
+IS  - 1
+SP  - 90
+EP  - 100
+DO  - 10.1016/j.chembiol.2010.11.010
+UR  - https://identifiers.org/pubmed:21183019
+N1  - MIBiG: BGC0000535, BGC0001070
+ER  - 
+
+TY  - GEN
+ID  - pubmed:4554808
+UR  - https://identifiers.org/pubmed:4554808
+N1  - MIBiG: BGC0001070
+ER  - 
+
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected RIS output:\n%s", diff.LineDiff(expected, out.String()))
+	}
+}
@@ -0,0 +1,182 @@
+// Package cached wraps a MibigModel, keeping the results of the expensive aggregation
+// queries in memory. The underlying data only changes at release time, so the cache is
+// flushed by expiry or explicit invalidation.
+package cached
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type item struct {
+	value   interface{}
+	expires time.Time
+}
+
+// MibigModel caches statistics, the release history, the repository listing and the result
+// statistics of recent queries. All other methods are passed through to the wrapped model.
+type MibigModel struct {
+	models.MibigModel
+
+	ttl        time.Duration
+	maxQueries int
+
+	mu    sync.Mutex
+	items map[string]item
+	// queryKeys holds the keys of cached result statistics, least recently used first
+	queryKeys    []string
+	lastModified time.Time
+
+	// now can be replaced in tests
+	now func() time.Time
+}
+
+// NewMibigModel wraps model with a cache keeping entries for ttl and the result
+// statistics of up to maxQueries queries. A ttl of 0 disables caching.
+func NewMibigModel(model models.MibigModel, ttl time.Duration, maxQueries int) *MibigModel {
+	m := &MibigModel{
+		MibigModel: model,
+		ttl:        ttl,
+		maxQueries: maxQueries,
+		items:      make(map[string]item),
+		now:        time.Now,
+	}
+	m.lastModified = m.now()
+	return m
+}
+
+// Invalidate drops all cached values
+func (m *MibigModel) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[string]item)
+	m.queryKeys = nil
+	m.lastModified = m.now()
+}
+
+// LastModified returns the time the cache was last invalidated, or a reload after expiry
+// returned a changed value. It is zero with caching disabled, as changes aren't tracked then.
+func (m *MibigModel) LastModified() time.Time {
+	if m.ttl <= 0 {
+		return time.Time{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastModified
+}
+
+func (m *MibigModel) get(key string, load func() (interface{}, error)) (interface{}, error) {
+	if m.ttl <= 0 {
+		return load()
+	}
+
+	m.mu.Lock()
+	cached, ok := m.items[key]
+	m.mu.Unlock()
+	if ok && m.now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	// Don't hold the lock while querying, a duplicate load is cheaper than blocking all readers
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if ok && !reflect.DeepEqual(cached.value, value) {
+		m.lastModified = m.now()
+	}
+	m.items[key] = item{value: value, expires: m.now().Add(m.ttl)}
+	m.mu.Unlock()
+	return value, nil
+}
+
+func (m *MibigModel) Counts() (*models.StatCounts, error) {
+	value, err := m.get("counts", func() (interface{}, error) { return m.MibigModel.Counts() })
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.StatCounts), nil
+}
+
+func (m *MibigModel) ClusterStats() ([]models.StatCluster, error) {
+	value, err := m.get("cluster_stats", func() (interface{}, error) { return m.MibigModel.ClusterStats() })
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.StatCluster), nil
+}
+
+func (m *MibigModel) GenusStats() ([]models.TaxonStats, error) {
+	value, err := m.get("genus_stats", func() (interface{}, error) { return m.MibigModel.GenusStats() })
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.TaxonStats), nil
+}
+
+func (m *MibigModel) Repository() ([]models.RepositoryEntry, error) {
+	value, err := m.get("repository", func() (interface{}, error) { return m.MibigModel.Repository() })
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.RepositoryEntry), nil
+}
+
+func (m *MibigModel) ReleaseHistory() ([]*models.ReleaseStats, error) {
+	value, err := m.get("release_history", func() (interface{}, error) { return m.MibigModel.ReleaseHistory() })
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*models.ReleaseStats), nil
+}
+
+func (m *MibigModel) ResultStats(ids []int) (*models.ResultStats, error) {
+	key := resultStatsKey(ids)
+	m.trackQuery(key)
+
+	value, err := m.get(key, func() (interface{}, error) { return m.MibigModel.ResultStats(ids) })
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.ResultStats), nil
+}
+
+// trackQuery remembers the order result statistics were last requested in,
+// evicting the least recently used ones beyond maxQueries, so popular queries stay cached
+func (m *MibigModel) trackQuery(key string) {
+	if m.ttl <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.queryKeys {
+		if existing == key {
+			m.queryKeys = append(m.queryKeys[:i], m.queryKeys[i+1:]...)
+			break
+		}
+	}
+
+	m.queryKeys = append(m.queryKeys, key)
+	for len(m.queryKeys) > m.maxQueries {
+		delete(m.items, m.queryKeys[0])
+		m.queryKeys = m.queryKeys[1:]
+	}
+}
+
+func resultStatsKey(ids []int) string {
+	hash := sha1.New()
+	for _, id := range ids {
+		hash.Write([]byte(strconv.Itoa(id)))
+		hash.Write([]byte{','})
+	}
+	return "result_stats:" + hex.EncodeToString(hash.Sum(nil))
+}
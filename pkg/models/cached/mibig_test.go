@@ -0,0 +1,154 @@
+package cached
+
+import (
+	"testing"
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/mock"
+)
+
+type countingModel struct {
+	mock.MibigModel
+	counts         int
+	resultStats    int
+	releaseHistory int
+	// added is added to the total count, to simulate a data change
+	added int
+}
+
+func (m *countingModel) Counts() (*models.StatCounts, error) {
+	m.counts++
+	counts, err := m.MibigModel.Counts()
+	if err != nil {
+		return nil, err
+	}
+	counts.Total += m.added
+	return counts, nil
+}
+
+func (m *countingModel) ResultStats(ids []int) (*models.ResultStats, error) {
+	m.resultStats++
+	return m.MibigModel.ResultStats(ids)
+}
+
+func (m *countingModel) ReleaseHistory() ([]*models.ReleaseStats, error) {
+	m.releaseHistory++
+	return m.MibigModel.ReleaseHistory()
+}
+
+func TestCounts(t *testing.T) {
+	inner := &countingModel{}
+	model := NewMibigModel(inner, time.Minute, 10)
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	model.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		counts, err := model.Counts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts.Total != 23 {
+			t.Errorf("Expected %d, got %d", 23, counts.Total)
+		}
+	}
+	if inner.counts != 1 {
+		t.Errorf("Expected 1 query before expiry, got %d", inner.counts)
+	}
+
+	now = now.Add(2 * time.Minute)
+	model.Counts()
+	if inner.counts != 2 {
+		t.Errorf("Expected 2 queries after expiry, got %d", inner.counts)
+	}
+
+	now = now.Add(time.Second)
+	model.Invalidate()
+	model.Counts()
+	if inner.counts != 3 {
+		t.Errorf("Expected 3 queries after invalidation, got %d", inner.counts)
+	}
+	if !model.LastModified().Equal(now) {
+		t.Errorf("Expected last modified %s, got %s", now, model.LastModified())
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	inner := &countingModel{}
+	model := NewMibigModel(inner, time.Minute, 10)
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	model.now = func() time.Time { return now }
+	model.Invalidate()
+
+	model.Counts()
+	now = now.Add(2 * time.Minute)
+	model.Counts()
+	if !model.LastModified().Equal(start) {
+		t.Errorf("Expected reload of unchanged data to keep last modified %s, got %s", start, model.LastModified())
+	}
+
+	inner.added = 1
+	now = now.Add(2 * time.Minute)
+	model.Counts()
+	if !model.LastModified().Equal(now) {
+		t.Errorf("Expected reload of changed data to set last modified %s, got %s", now, model.LastModified())
+	}
+
+	if !NewMibigModel(inner, 0, 10).LastModified().IsZero() {
+		t.Errorf("Expected no last modified time with caching disabled")
+	}
+}
+
+func TestResultStats(t *testing.T) {
+	inner := &countingModel{}
+	model := NewMibigModel(inner, time.Minute, 2)
+
+	queries := [][]int{{1, 2}, {1, 2}, {3}, {1, 2}, {4}, {1, 2}, {5}, {1, 2}}
+	for _, ids := range queries {
+		if _, err := model.ResultStats(ids); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// {1, 2} is used again before each new query, so it survives while {3} and {4} are evicted
+	if inner.resultStats != 4 {
+		t.Errorf("Expected %d queries, got %d", 4, inner.resultStats)
+	}
+
+	model.ResultStats([]int{3})
+	if inner.resultStats != 5 {
+		t.Errorf("Expected evicted query to be loaded again, got %d queries", inner.resultStats)
+	}
+}
+
+func TestReleaseHistory(t *testing.T) {
+	inner := &countingModel{}
+	model := NewMibigModel(inner, time.Minute, 10)
+
+	for i := 0; i < 3; i++ {
+		if _, err := model.ReleaseHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.releaseHistory != 1 {
+		t.Errorf("Expected 1 query before expiry, got %d", inner.releaseHistory)
+	}
+
+	model.Invalidate()
+	model.ReleaseHistory()
+	if inner.releaseHistory != 2 {
+		t.Errorf("Expected 2 queries after invalidation, got %d", inner.releaseHistory)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	inner := &countingModel{}
+	model := NewMibigModel(inner, 0, 10)
+
+	model.Counts()
+	model.Counts()
+	if inner.counts != 2 {
+		t.Errorf("Expected %d queries with caching disabled, got %d", 2, inner.counts)
+	}
+}
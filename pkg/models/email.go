@@ -15,6 +15,7 @@ type MailConfig struct {
 
 type EmailSender interface {
 	Send(from string, body []byte) error
+	SendTo(to string, body []byte) error
 	Config() *MailConfig
 }
 
@@ -29,6 +30,13 @@ func (e *emailSender) Send(from string, body []byte) error {
 	return e.send(addr, auth, from, []string{e.conf.Recipient}, body)
 }
 
+// SendTo sends a mail from the configured recipient address to a user
+func (e *emailSender) SendTo(to string, body []byte) error {
+	addr := fmt.Sprintf("%s:%d", e.conf.Host, e.conf.Port)
+	auth := smtp.PlainAuth("", e.conf.Username, e.conf.Password, e.conf.Host)
+	return e.send(addr, auth, e.conf.Recipient, []string{to}, body)
+}
+
 func (e *emailSender) Config() *MailConfig {
 	return &e.conf
 }
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ReleaseCounts struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Retired int `json:"retired"`
+	// Migrated counts entries carried over from an earlier release without a changelog of their own
+	Migrated int `json:"migrated"`
+}
+
+// ReleaseStats summarises the changes to the repository in one MIBiG release
+type ReleaseStats struct {
+	Version string `json:"version"`
+	ReleaseCounts
+	ByClass        map[string]*ReleaseCounts `json:"by_class"`
+	ByCompleteness map[string]*ReleaseCounts `json:"by_completeness"`
+}
+
+// ReleaseHistory counts the entries added, updated and retired per release, based on the
+// entries' changelogs. The first version an entry appears in counts as added, later versions
+// as updates. Retired entries count as retired in their latest version instead.
+// Entries whose changelog starts with a migration from an older release predate their first
+// version, so they count as migrated rather than added there.
+func ReleaseHistory(entries []Entry) ([]*ReleaseStats, error) {
+	releases := make(map[string]*ReleaseStats)
+
+	for i := range entries {
+		data, err := entries[i].Details()
+		if err != nil {
+			return nil, err
+		}
+
+		versions := changelogVersions(data.Changelog)
+		completeness := data.Cluster.Loci.Completeness
+		if completeness == "" {
+			completeness = "unknown"
+		}
+		retired := data.Cluster.Status == "retired"
+		migrated := len(versions) > 0 && isMigration(data.Changelog, versions[0])
+
+		for j, version := range versions {
+			release, ok := releases[version]
+			if !ok {
+				release = &ReleaseStats{
+					Version:        version,
+					ByClass:        make(map[string]*ReleaseCounts),
+					ByCompleteness: make(map[string]*ReleaseCounts),
+				}
+				releases[version] = release
+			}
+
+			counters := []*ReleaseCounts{&release.ReleaseCounts, countsFor(release.ByCompleteness, completeness)}
+			for _, class := range data.Cluster.BiosynClass {
+				counters = append(counters, countsFor(release.ByClass, class))
+			}
+
+			for _, counter := range counters {
+				// An entry retired in the release that introduced it only counts as retired
+				if j == len(versions)-1 && retired {
+					counter.Retired++
+				} else if j == 0 && migrated {
+					counter.Migrated++
+				} else if j == 0 {
+					counter.Added++
+				} else {
+					counter.Updated++
+				}
+			}
+		}
+	}
+
+	history := make([]*ReleaseStats, 0, len(releases))
+	for _, release := range releases {
+		history = append(history, release)
+	}
+	sort.Slice(history, func(i, j int) bool { return CompareVersions(history[i].Version, history[j].Version) < 0 })
+	return history, nil
+}
+
+func countsFor(counts map[string]*ReleaseCounts, key string) *ReleaseCounts {
+	if c, ok := counts[key]; ok {
+		return c
+	}
+	c := &ReleaseCounts{}
+	counts[key] = c
+	return c
+}
+
+// isMigration reports whether the changelog entries of version record a migration from an
+// older release, like the "Migrated from v1.4" comments of the 2.0 release
+func isMigration(changelog []ChangelogEntry, version string) bool {
+	for _, change := range changelog {
+		if strings.TrimSpace(change.Version) != version {
+			continue
+		}
+		for _, comment := range change.Comments {
+			if strings.HasPrefix(strings.ToLower(strings.TrimSpace(comment)), "migrated from") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changelogVersions returns the distinct versions of a changelog, oldest first
+func changelogVersions(changelog []ChangelogEntry) []string {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, change := range changelog {
+		version := strings.TrimSpace(change.Version)
+		if version == "" || seen[version] {
+			continue
+		}
+		seen[version] = true
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+	return versions
+}
+
+// CompareVersions compares dotted version strings numerically, so "1.10" sorts after "1.9".
+// Non-numeric parts are compared as strings.
+func CompareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if aPart == bPart {
+			continue
+		}
+
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		case aPart == "":
+			return -1
+		case bPart == "":
+			return 1
+		case aPart < bPart:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
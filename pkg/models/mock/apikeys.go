@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+// ApiKeyModel keeps API keys and their secrets in memory
+type ApiKeyModel struct {
+	keys    map[string]models.ApiKey
+	secrets map[string]string
+}
+
+func (m *ApiKeyModel) Create(userId string, name string, scopes []string, expires time.Time) (*models.ApiKey, string, error) {
+	if m.keys == nil {
+		m.keys = make(map[string]models.ApiKey)
+		m.secrets = make(map[string]string)
+	}
+
+	id, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := models.ApiKey{Id: id, UserId: userId, Name: name, Scopes: scopes, Created: time.Now(), Expires: expires}
+	m.keys[id] = key
+	m.secrets[id] = secret
+	return &key, id + "." + secret, nil
+}
+
+func (m *ApiKeyModel) List(userId string) ([]models.ApiKey, error) {
+	var keys []models.ApiKey
+	for _, key := range m.keys {
+		if key.UserId == userId {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created.Before(keys[j].Created) })
+	return keys, nil
+}
+
+func (m *ApiKeyModel) Authenticate(secret string) (*models.ApiKey, error) {
+	parts := strings.SplitN(secret, ".", 2)
+	if len(parts) != 2 {
+		return nil, models.ErrInvalidToken
+	}
+	key, ok := m.keys[parts[0]]
+	if !ok || m.secrets[key.Id] != parts[1] || (!key.Expires.IsZero() && !time.Now().Before(key.Expires)) {
+		return nil, models.ErrInvalidToken
+	}
+	key.LastUsed = time.Now()
+	m.keys[key.Id] = key
+	return &key, nil
+}
+
+func (m *ApiKeyModel) Revoke(userId string, keyId string) error {
+	key, ok := m.keys[keyId]
+	if !ok || key.UserId != userId {
+		return models.ErrNotFound
+	}
+	delete(m.keys, keyId)
+	delete(m.secrets, keyId)
+	return nil
+}
+
+func (m *ApiKeyModel) RevokeAll(userId string) error {
+	for id, key := range m.keys {
+		if key.UserId == userId {
+			delete(m.keys, id)
+			delete(m.secrets, id)
+		}
+	}
+	return nil
+}
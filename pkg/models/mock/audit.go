@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// AuditModel keeps the audit log in memory
+type AuditModel struct {
+	Entries []models.AuditEntry
+}
+
+func (m *AuditModel) Record(entry *models.AuditEntry) error {
+	entry.Id = int64(len(m.Entries) + 1)
+	entry.Timestamp = time.Now()
+	m.Entries = append(m.Entries, *entry)
+	return nil
+}
+
+func (m *AuditModel) List(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	for i := len(m.Entries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) >= filter.Limit {
+			break
+		}
+		if filter.Matches(&m.Entries[i]) {
+			entries = append(entries, m.Entries[i])
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// LockoutModel keeps failed logins in memory
+type LockoutModel struct {
+	lockouts map[string]models.Lockout
+}
+
+func (m *LockoutModel) Get(userId string) (*models.Lockout, error) {
+	lockout, ok := m.lockouts[userId]
+	if !ok {
+		return &models.Lockout{UserId: userId}, nil
+	}
+	return &lockout, nil
+}
+
+func (m *LockoutModel) RecordFailure(userId string, maxFailures int, duration time.Duration) (*models.Lockout, error) {
+	if m.lockouts == nil {
+		m.lockouts = make(map[string]models.Lockout)
+	}
+	lockout := m.lockouts[userId]
+	lockout.UserId = userId
+	lockout.Failures++
+	if lockout.Failures >= maxFailures {
+		lockout.Failures = 0
+		lockout.LockedUntil = time.Now().Add(duration)
+	}
+	m.lockouts[userId] = lockout
+	return &lockout, nil
+}
+
+func (m *LockoutModel) Reset(userId string) error {
+	delete(m.lockouts, userId)
+	return nil
+}
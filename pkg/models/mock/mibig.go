@@ -1,8 +1,11 @@
 package mock
 
 import (
+	"strings"
+
 	"secondarymetabolites.org/mibig-api/pkg/models"
 	"secondarymetabolites.org/mibig-api/pkg/queries"
+	"secondarymetabolites.org/mibig-api/pkg/taxonomy"
 )
 
 type MibigModel struct {
@@ -128,3 +131,186 @@ func (m *MibigModel) LookupContributors(ids []string) ([]models.Contributor, err
 	}
 	return contributors, nil
 }
+
+var fakeEntry = models.Entry{
+	ID:    1,
+	Acc:   "BGC0000001",
+	TaxID: 1901,
+	Data: models.JsonData{
+		"cluster": map[string]interface{}{
+			"mibig_accession": "BGC0000001",
+			"organism_name":   "E. xample",
+			"ncbi_tax_id":     "1901",
+			"biosyn_class":    []interface{}{"NRP"},
+			"minimal":         false,
+			"loci": map[string]interface{}{
+				"accession":    "ABC12345.1",
+				"completeness": "incomplete",
+				"evidence":     []interface{}{"Knock-out studies"},
+			},
+			"compounds": []interface{}{
+				map[string]interface{}{
+					"compound":          "testomycin A",
+					"chem_struct":       "CC(=O)O",
+					"molecular_formula": "C2H4O2",
+					"mol_mass":          60.052,
+					"chem_acts":         []interface{}{"Antibacterial"},
+					"database_id":       []interface{}{"pubchem:176"},
+					"chem_synonyms":     []interface{}{"testomycin"},
+				},
+			},
+			"publications": []interface{}{"pubmed:12345"},
+		},
+		"changelog": []interface{}{
+			map[string]interface{}{
+				"version":      "2.0",
+				"comments":     []interface{}{"Migrated from v1.4"},
+				"contributors": []interface{}{"AAAAAAAAAAAAAAAAAAAAAAAA"},
+			},
+		},
+	},
+	Taxon: &models.Taxon{
+		TaxID:        1901,
+		Superkingdom: "Bacteria",
+		Phylum:       "Actinobacteria",
+		Class:        "Actinobacteria",
+		Order:        "Streptomycetales",
+		Family:       "Streptomycetaceae",
+		Genus:        "Examplomyces",
+		Species:      "exemplaris",
+		Name:         "E. xample",
+	},
+}
+
+func (m *MibigModel) GetEntry(accession string) (*models.Entry, error) {
+	if accession != fakeEntry.Acc {
+		return nil, models.ErrNotFound
+	}
+	return &fakeEntry, nil
+}
+
+func (m *MibigModel) Entries(ids []int) ([]models.Entry, error) {
+	if ids == nil {
+		return []models.Entry{fakeEntry}, nil
+	}
+	for _, id := range ids {
+		if id == fakeEntry.ID {
+			return []models.Entry{fakeEntry}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MibigModel) ReleaseHistory() ([]*models.ReleaseStats, error) {
+	entries, err := m.Entries(nil)
+	if err != nil {
+		return nil, err
+	}
+	return models.ReleaseHistory(entries)
+}
+
+func (m *MibigModel) EntryIds(accessions []string) ([]int, error) {
+	var entry_ids []int
+	for _, accession := range accessions {
+		if accession == fakeEntry.Acc {
+			entry_ids = append(entry_ids, fakeEntry.ID)
+		}
+	}
+	return entry_ids, nil
+}
+
+func (m *MibigModel) TaxonCounts(ids []int) ([]models.TaxonCount, error) {
+	return []models.TaxonCount{
+		{Taxon: *fakeEntry.Taxon, Count: 2},
+		{Taxon: models.Taxon{TaxID: 1360, Superkingdom: "Bacteria", Phylum: "Firmicutes", Class: "Bacilli",
+			Order: "Lactobacillales", Family: "Streptococcaceae", Genus: "Lactococcus", Species: "lactis",
+			Name: "Lactococcus lactis subsp. lactis"}, Count: 1},
+	}, nil
+}
+
+func (m *MibigModel) TaxonomyNode(rank string, name string) (*models.TaxonomyNode, error) {
+	found := false
+	for _, r := range models.TaxonomicRanks {
+		if r == rank {
+			found = true
+		}
+	}
+	if !found {
+		return nil, models.ErrInvalidRank
+	}
+
+	if !strings.EqualFold(fakeEntry.Taxon.RankValue(rank), name) {
+		return nil, models.ErrNotFound
+	}
+	return taxonomy.Browse(rank, name, []models.Entry{fakeEntry}), nil
+}
+
+func (m *MibigModel) Compound(name string) (*models.CompoundDetails, error) {
+	details, err := models.CompoundsNamed([]models.Entry{fakeEntry}, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(details.Entries) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return details, nil
+}
+
+var fakeCompounds = []models.CompoundSummary{
+	{Name: "nisin A", Count: 1},
+	{Name: "testomycin A", Count: 1},
+	{Name: "testomycin B", Count: 2},
+}
+
+func (m *MibigModel) Compounds(offset int, limit int) ([]models.CompoundSummary, int, error) {
+	if offset > len(fakeCompounds) {
+		offset = len(fakeCompounds)
+	}
+	end := len(fakeCompounds)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return fakeCompounds[offset:end], len(fakeCompounds), nil
+}
+
+var fakeTypes = []models.BgcType{
+	{Id: 1, Term: "PKS", Description: "Polyketide", Class: "pks"},
+	{Id: 2, ParentId: 1, Term: "T1PKS", Description: "Type I PKS", Class: "pks"},
+	{Id: 3, Term: "NRP", Description: "Nonribosomal peptide", Class: "nrps"},
+}
+
+func (m *MibigModel) TypeTree() ([]*models.BgcType, error) {
+	return models.BuildTypeTree(fakeTypes, map[int][]int{1: {3}, 2: {2}, 3: {1, 3}}), nil
+}
+
+func (m *MibigModel) CrossTab(ids []int, rows string, columns string) (*models.CrossTab, error) {
+	valid := map[string]bool{}
+	for _, dimension := range models.CrossTabDimensions {
+		valid[dimension] = true
+	}
+	if !valid[rows] || !valid[columns] {
+		return nil, models.ErrInvalidDimension
+	}
+
+	return models.BuildCrossTab([]models.CrossTabCell{
+		{Row: "NRP", Column: "Actinobacteria", Count: 2},
+		{Row: "Polyketide", Column: "Firmicutes", Count: 1},
+	}), nil
+}
+
+func (m *MibigModel) ContributorProfile(id string) (*models.ContributorProfile, error) {
+	contributors, _ := m.LookupContributors([]string{id})
+	if len(contributors) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	contributions, err := m.Contributions(id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ContributorProfile{Contributor: contributors[0], Contributions: contributions}, nil
+}
+
+func (m *MibigModel) Contributions(userId string) ([]models.Contribution, error) {
+	return models.ContributionsBy([]models.Entry{fakeEntry}, userId)
+}
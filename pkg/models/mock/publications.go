@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type PublicationModel struct {
+}
+
+var fakePublications = map[string]models.Publication{
+	"pubmed:12345": models.Publication{
+		Id:      "pubmed:12345",
+		Title:   "Testomycin biosynthesis & you",
+		Authors: []string{"User, Alice", "User, Bob"},
+		Journal: "J Test",
+		Year:    2020,
+		Volume:  "23",
+		Issue:   "4",
+		Pages:   "17-42",
+		Doi:     "10.1000/test.12345",
+	},
+}
+
+func (m *PublicationModel) Get(ids []string) ([]models.Publication, error) {
+	var publications []models.Publication
+	for _, id := range ids {
+		if publication, ok := fakePublications[id]; ok {
+			publications = append(publications, publication)
+		}
+	}
+	return publications, nil
+}
+
+func (m *PublicationModel) Upsert(publications []models.Publication) error {
+	return nil
+}
+
+func (m *PublicationModel) Missing() ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,124 @@
+package mock
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+// SessionModel keeps sessions and their current refresh secrets in memory
+type SessionModel struct {
+	sessions map[string]models.Session
+	secrets  map[string]string
+}
+
+// NewSessionModel returns a model holding one session for AAAAAAAAAAAAAAAAAAAAAAAA,
+// BBBBBBBBBBBBBBBBBBBBBBBB with the refresh token "BBBBBBBBBBBBBBBBBBBBBBBB.secret",
+// and one for AAAAAAAAAAAAAAAAAAAAAAAB, BBBBBBBBBBBBBBBBBBBBBBBC with the refresh token "BBBBBBBBBBBBBBBBBBBBBBBC.secret"
+func NewSessionModel() *SessionModel {
+	return &SessionModel{
+		sessions: map[string]models.Session{
+			"BBBBBBBBBBBBBBBBBBBBBBBB": {
+				Id:      "BBBBBBBBBBBBBBBBBBBBBBBB",
+				UserId:  "AAAAAAAAAAAAAAAAAAAAAAAA",
+				Created: time.Now(),
+				Expires: time.Now().Add(24 * time.Hour),
+			},
+			"BBBBBBBBBBBBBBBBBBBBBBBC": {
+				Id:      "BBBBBBBBBBBBBBBBBBBBBBBC",
+				UserId:  "AAAAAAAAAAAAAAAAAAAAAAAB",
+				Created: time.Now(),
+				Expires: time.Now().Add(24 * time.Hour),
+			},
+		},
+		secrets: map[string]string{
+			"BBBBBBBBBBBBBBBBBBBBBBBB": "secret",
+			"BBBBBBBBBBBBBBBBBBBBBBBC": "secret",
+		},
+	}
+}
+
+func (m *SessionModel) Create(userId string, lifetime time.Duration) (*models.Session, string, error) {
+	id, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	session := models.Session{Id: id, UserId: userId, Created: time.Now(), Expires: time.Now().Add(lifetime)}
+	m.sessions[id] = session
+	m.secrets[id] = secret
+	return &session, id + "." + secret, nil
+}
+
+func (m *SessionModel) Refresh(refreshToken string, lifetime time.Duration) (*models.Session, string, error) {
+	parts := strings.SplitN(refreshToken, ".", 2)
+	if len(parts) != 2 {
+		return nil, "", models.ErrInvalidToken
+	}
+	session, err := m.Get(parts[0])
+	if err != nil {
+		return nil, "", models.ErrInvalidToken
+	}
+	if m.secrets[session.Id] != parts[1] {
+		m.Revoke(session.Id)
+		return nil, "", models.ErrInvalidToken
+	}
+
+	secret, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	session.Expires = time.Now().Add(lifetime)
+	m.sessions[session.Id] = *session
+	m.secrets[session.Id] = secret
+	return session, session.Id + "." + secret, nil
+}
+
+func (m *SessionModel) Get(sessionId string) (*models.Session, error) {
+	session, ok := m.sessions[sessionId]
+	if !ok || !time.Now().Before(session.Expires) {
+		return nil, models.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (m *SessionModel) List(userId string) ([]models.Session, error) {
+	var sessions []models.Session
+	for _, session := range m.sessions {
+		if session.UserId == userId && time.Now().Before(session.Expires) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Created.Before(sessions[j].Created) })
+	return sessions, nil
+}
+
+func (m *SessionModel) Revoke(sessionId string) error {
+	delete(m.sessions, sessionId)
+	delete(m.secrets, sessionId)
+	return nil
+}
+
+func (m *SessionModel) RevokeAll(userId string) error {
+	for id, session := range m.sessions {
+		if session.UserId == userId {
+			m.Revoke(id)
+		}
+	}
+	return nil
+}
+
+func (m *SessionModel) Expire() error {
+	for id, session := range m.sessions {
+		if !time.Now().Before(session.Expires) {
+			m.Revoke(id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package mock
+
+import (
+	"database/sql"
+	"sort"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+// SubmitterModel keeps submitters in memory, so handlers changing accounts can be tested
+type SubmitterModel struct {
+	submitters map[string]*models.Submitter
+	passwords  map[string]string
+}
+
+var fakeRoles = []models.Role{
+	{Id: 1, Name: "admin", Description: "Users who can manage other users"},
+	{Id: 2, Name: "curator", Description: "Users who can approve submissions"},
+	{Id: 3, Name: "submitter", Description: "Users who can submit new entries"},
+	{Id: 4, Name: "guest", Description: "Users with read only access"},
+}
+
+// NewSubmitterModel returns a model holding an active submitter,
+// AAAAAAAAAAAAAAAAAAAAAAAA / alice@example.com with the password "secret",
+// and an active admin, AAAAAAAAAAAAAAAAAAAAAAAB / admin@example.com with the password "admin secret"
+func NewSubmitterModel() *SubmitterModel {
+	return &SubmitterModel{
+		submitters: map[string]*models.Submitter{
+			"AAAAAAAAAAAAAAAAAAAAAAAA": {
+				Id:          "AAAAAAAAAAAAAAAAAAAAAAAA",
+				Email:       "alice@example.com",
+				Name:        "Alice User",
+				CallName:    "Alice",
+				Institution: "Testing",
+				Public:      true,
+				GDPRConsent: true,
+				Active:      true,
+				Roles:       []models.Role{fakeRoles[2]},
+			},
+			"AAAAAAAAAAAAAAAAAAAAAAAB": {
+				Id:          "AAAAAAAAAAAAAAAAAAAAAAAB",
+				Email:       "admin@example.com",
+				Name:        "Admin User",
+				CallName:    "Admin",
+				Institution: "Testing",
+				GDPRConsent: true,
+				Active:      true,
+				Roles:       []models.Role{fakeRoles[0]},
+			},
+		},
+		passwords: map[string]string{
+			"AAAAAAAAAAAAAAAAAAAAAAAA": "secret",
+			"AAAAAAAAAAAAAAAAAAAAAAAB": "admin secret",
+		},
+	}
+}
+
+func (m *SubmitterModel) Ping() error {
+	return nil
+}
+
+func (m *SubmitterModel) Insert(submitter *models.Submitter, password string) error {
+	for _, existing := range m.submitters {
+		if existing.Email == submitter.Email {
+			return models.ErrDuplicateEmail
+		}
+	}
+	if submitter.Id == "" {
+		var err error
+		submitter.Id, err = utils.GenerateUid(15)
+		if err != nil {
+			return err
+		}
+	}
+	stored := *submitter
+	m.submitters[submitter.Id] = &stored
+	m.passwords[submitter.Id] = password
+	return nil
+}
+
+func (m *SubmitterModel) GetRolesById(role_ids []int64) ([]models.Role, error) {
+	var roles []models.Role
+	for _, id := range role_ids {
+		for _, role := range fakeRoles {
+			if int64(role.Id) == id {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles, nil
+}
+
+// GetRolesByName fails with sql.ErrNoRows for unknown names, like the postgres model
+func (m *SubmitterModel) GetRolesByName(role_names []string) ([]models.Role, error) {
+	var roles []models.Role
+	for _, name := range role_names {
+		found := false
+		for _, role := range fakeRoles {
+			if role.Name == name {
+				roles = append(roles, role)
+				found = true
+			}
+		}
+		if !found {
+			return nil, sql.ErrNoRows
+		}
+	}
+	return roles, nil
+}
+
+func (m *SubmitterModel) Get(email string, active_only bool) (*models.Submitter, error) {
+	for _, submitter := range m.submitters {
+		if submitter.Email == email && (submitter.Active || !active_only) {
+			found := *submitter
+			return &found, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *SubmitterModel) GetById(userId string) (*models.Submitter, error) {
+	submitter, ok := m.submitters[userId]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	found := *submitter
+	return &found, nil
+}
+
+func (m *SubmitterModel) Authenticate(email, password string) (*models.Submitter, error) {
+	submitter, err := m.Get(email, true)
+	if err != nil || m.passwords[submitter.Id] != password {
+		return nil, models.ErrInvalidCredentials
+	}
+	return submitter, nil
+}
+
+func (m *SubmitterModel) ChangePassword(userId string, password string) error {
+	if _, ok := m.submitters[userId]; !ok {
+		return models.ErrNotFound
+	}
+	m.passwords[userId] = password
+	return nil
+}
+
+func (m *SubmitterModel) Update(submitter *models.Submitter, password string) error {
+	if _, ok := m.submitters[submitter.Id]; !ok {
+		return models.ErrNotFound
+	}
+	stored := *submitter
+	m.submitters[submitter.Id] = &stored
+	if password != "" {
+		m.passwords[submitter.Id] = password
+	}
+	return nil
+}
+
+func (m *SubmitterModel) List() ([]models.Submitter, error) {
+	var submitters []models.Submitter
+	for _, submitter := range m.submitters {
+		submitters = append(submitters, *submitter)
+	}
+	sort.Slice(submitters, func(i, j int) bool { return submitters[i].Id < submitters[j].Id })
+	return submitters, nil
+}
+
+func (m *SubmitterModel) Delete(email string) error {
+	submitter, err := m.Get(email, false)
+	if err != nil {
+		return err
+	}
+	delete(m.submitters, submitter.Id)
+	delete(m.passwords, submitter.Id)
+	return nil
+}
+
+func (m *SubmitterModel) Anonymise(userId string) error {
+	if _, ok := m.submitters[userId]; !ok {
+		return models.ErrNotFound
+	}
+	m.submitters[userId] = &models.Submitter{Id: userId, Email: userId + "@anonymised.invalid"}
+	delete(m.passwords, userId)
+	return nil
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// TokenModel keeps tokens in memory and issues them for the users of a SubmitterModel
+type TokenModel struct {
+	Submitters *SubmitterModel
+	tokens     map[string]models.Token
+}
+
+func (m *TokenModel) Generate(email string, purpose string, lifetime time.Duration) (*models.Token, error) {
+	submitter, err := m.Submitters.Get(email, false)
+	if err != nil {
+		return nil, models.ErrNotFound
+	}
+
+	if m.tokens == nil {
+		m.tokens = make(map[string]models.Token)
+	}
+	token := models.Token{
+		Token:   "TOKEN" + submitter.Id + purpose,
+		UserId:  submitter.Id,
+		Purpose: purpose,
+		Expires: time.Now().Add(lifetime),
+	}
+	m.tokens[token.Token] = token
+	return &token, nil
+}
+
+func (m *TokenModel) Validate(token string, purpose string) (string, error) {
+	stored, ok := m.tokens[token]
+	if !ok || stored.Purpose != purpose || !time.Now().Before(stored.Expires) {
+		return "", models.ErrInvalidToken
+	}
+	// Tokens of deleted users are removed with them in the database
+	if _, err := m.Submitters.GetById(stored.UserId); err != nil {
+		return "", models.ErrInvalidToken
+	}
+	return stored.UserId, nil
+}
+
+func (m *TokenModel) Remove(token string) error {
+	if _, ok := m.tokens[token]; !ok {
+		return models.ErrInvalidToken
+	}
+	delete(m.tokens, token)
+	return nil
+}
+
+func (m *TokenModel) RemoveAll(userId string) error {
+	for key, token := range m.tokens {
+		if token.UserId == userId {
+			delete(m.tokens, key)
+		}
+	}
+	return nil
+}
+
+func (m *TokenModel) Expire() error {
+	for key, token := range m.tokens {
+		if !time.Now().Before(token.Expires) {
+			delete(m.tokens, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// TwoFactorModel keeps TOTP enrolments and recovery codes in memory
+type TwoFactorModel struct {
+	enrolments    map[string]models.TwoFactor
+	recoveryCodes map[string]map[string]bool
+}
+
+func (m *TwoFactorModel) Get(userId string) (*models.TwoFactor, error) {
+	twoFactor, ok := m.enrolments[userId]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &twoFactor, nil
+}
+
+func (m *TwoFactorModel) Enrol(userId string, secret string) error {
+	if m.enrolments == nil {
+		m.enrolments = make(map[string]models.TwoFactor)
+		m.recoveryCodes = make(map[string]map[string]bool)
+	}
+	m.enrolments[userId] = models.TwoFactor{UserId: userId, Secret: secret}
+	return nil
+}
+
+func (m *TwoFactorModel) Confirm(userId string, recoveryCodes []string) error {
+	twoFactor, ok := m.enrolments[userId]
+	if !ok {
+		return models.ErrNotFound
+	}
+	twoFactor.Confirmed = true
+	m.enrolments[userId] = twoFactor
+
+	m.recoveryCodes[userId] = make(map[string]bool)
+	for _, code := range recoveryCodes {
+		m.recoveryCodes[userId][code] = true
+	}
+	return nil
+}
+
+func (m *TwoFactorModel) UseStep(userId string, step int64) error {
+	twoFactor, ok := m.enrolments[userId]
+	if !ok || twoFactor.LastStep >= step {
+		return models.ErrInvalidToken
+	}
+	twoFactor.LastStep = step
+	m.enrolments[userId] = twoFactor
+	return nil
+}
+
+func (m *TwoFactorModel) UseRecoveryCode(userId string, code string) error {
+	if !m.recoveryCodes[userId][code] {
+		return models.ErrInvalidToken
+	}
+	delete(m.recoveryCodes[userId], code)
+	return nil
+}
+
+func (m *TwoFactorModel) Disable(userId string) error {
+	delete(m.enrolments, userId)
+	delete(m.recoveryCodes, userId)
+	return nil
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
 	"secondarymetabolites.org/mibig-api/pkg/queries"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,6 +16,63 @@ type Entry struct {
 	Acc   string   `db:"acc"`
 	TaxID int      `db:"tax_id"`
 	Data  JsonData `db:"data"`
+	Taxon *Taxon   `db:"-"`
+}
+
+// Details decodes the raw entry JSON into the typed EntryData representation
+func (e *Entry) Details() (*EntryData, error) {
+	raw, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var data EntryData
+	if err = json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+type EntryData struct {
+	Cluster   ClusterData      `json:"cluster"`
+	Changelog []ChangelogEntry `json:"changelog"`
+}
+
+type ClusterData struct {
+	Accession    string       `json:"mibig_accession"`
+	OrganismName string       `json:"organism_name"`
+	NcbiTaxId    string       `json:"ncbi_tax_id"`
+	BiosynClass  []string     `json:"biosyn_class"`
+	Compounds    CompoundList `json:"compounds"`
+	Publications []string     `json:"publications"`
+	Minimal      bool         `json:"minimal"`
+	Loci         LociData     `json:"loci"`
+	Status       string       `json:"status,omitempty"`
+}
+
+type LociData struct {
+	Accession    string   `json:"accession"`
+	Completeness string   `json:"completeness"`
+	Evidence     []string `json:"evidence"`
+}
+
+type ChangelogEntry struct {
+	Version      string   `json:"version"`
+	Comments     []string `json:"comments"`
+	Contributors []string `json:"contributors"`
+}
+
+type Taxon struct {
+	TaxID        int    `json:"tax_id"`
+	Superkingdom string `json:"superkingdom"`
+	Kingdom      string `json:"kingdom"`
+	Phylum       string `json:"phylum"`
+	Class        string `json:"class"`
+	Order        string `json:"order"`
+	Family       string `json:"family"`
+	Genus        string `json:"genus"`
+	Species      string `json:"species"`
+	Name         string `json:"name"`
 }
 
 type StatCluster struct {
@@ -29,22 +89,181 @@ type StatCounts struct {
 	Incomplete int `json:"incomplete"`
 }
 
+// BgcType is a node of the biosynthetic class hierarchy in mibig.bgc_types
+type BgcType struct {
+	Id          int        `json:"-"`
+	ParentId    int        `json:"-"`
+	Term        string     `json:"term"`
+	Description string     `json:"description"`
+	Class       string     `json:"safe_class"`
+	Count       int        `json:"count"`
+	Children    []*BgcType `json:"children,omitempty"`
+}
+
+// BuildTypeTree nests the types by their parent ids, sorted by term.
+// entryTypes maps entry ids to their type ids; each type counts the distinct entries of
+// its own and all its subtypes.
+func BuildTypeTree(types []BgcType, entryTypes map[int][]int) []*BgcType {
+	byId := make(map[int]*BgcType, len(types))
+	for i := range types {
+		node := types[i]
+		node.Children = nil
+		node.Count = 0
+		byId[node.Id] = &node
+	}
+
+	var roots []*BgcType
+	for i := range types {
+		node := byId[types[i].Id]
+		if parent, ok := byId[node.ParentId]; ok && node.ParentId != node.Id {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	for _, typeIds := range entryTypes {
+		counted := make(map[int]bool)
+		for _, typeId := range typeIds {
+			for node, ok := byId[typeId]; ok && !counted[node.Id]; node, ok = byId[node.ParentId] {
+				counted[node.Id] = true
+				node.Count++
+			}
+		}
+	}
+
+	sortTypes(roots)
+	return roots
+}
+
+func sortTypes(types []*BgcType) {
+	sort.Slice(types, func(i, j int) bool { return types[i].Term < types[j].Term })
+	for _, t := range types {
+		sortTypes(t.Children)
+	}
+}
+
 type TaxonStats struct {
 	Genus string `json:"genus"`
 	Count int    `json:"count"`
 }
 
+type TaxonCount struct {
+	Taxon
+	Count int `json:"count"`
+}
+
+type TaxonLevel struct {
+	Rank  string `json:"rank"`
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type TaxonomyNode struct {
+	Rank     string       `json:"rank"`
+	Name     string       `json:"name"`
+	Count    int          `json:"count"`
+	Lineage  []TaxonLevel `json:"lineage"`
+	Children []TaxonLevel `json:"children"`
+	Entries  []string     `json:"entries"`
+}
+
+// TaxonomicRanks lists the ranks stored in mibig.taxa, from the most general to the most specific
+var TaxonomicRanks = []string{"superkingdom", "kingdom", "phylum", "class", "order", "family", "genus", "species"}
+
+// RankValue returns the taxon's name at the given rank, using the binomial name for species
+func (t *Taxon) RankValue(rank string) string {
+	switch rank {
+	case "superkingdom":
+		return t.Superkingdom
+	case "kingdom":
+		return t.Kingdom
+	case "phylum":
+		return t.Phylum
+	case "class":
+		return t.Class
+	case "order":
+		return t.Order
+	case "family":
+		return t.Family
+	case "genus":
+		return t.Genus
+	case "species":
+		if t.Species == "" || t.Genus == "" {
+			return t.Species
+		}
+		return t.Genus + " " + t.Species
+	}
+	return ""
+}
+
 type ProductTag struct {
 	Name  string `json:"name"`
 	Class string `json:"css_class"`
 }
 
+type CompoundTarget struct {
+	Target string `json:"target"`
+}
+
 type Compound struct {
-	Name string `json:"compound"`
+	Name        string           `json:"compound"`
+	Structure   string           `json:"chem_struct,omitempty"`
+	Formula     string           `json:"molecular_formula,omitempty"`
+	Mass        float64          `json:"mol_mass,omitempty"`
+	Activities  []string         `json:"chem_acts,omitempty"`
+	Targets     []CompoundTarget `json:"chem_targets,omitempty"`
+	DatabaseIds []string         `json:"database_id,omitempty"`
+	Synonyms    []string         `json:"chem_synonyms,omitempty"`
 }
 
 type CompoundList []Compound
 
+// Matches reports whether the compound has the given name or synonym, ignoring case
+func (c *Compound) Matches(name string) bool {
+	if strings.EqualFold(c.Name, name) {
+		return true
+	}
+	for _, synonym := range c.Synonyms {
+		if strings.EqualFold(synonym, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type CompoundOccurrence struct {
+	Accession string `json:"accession"`
+	Compound
+}
+
+type CompoundDetails struct {
+	Name    string               `json:"name"`
+	Entries []CompoundOccurrence `json:"entries"`
+}
+
+type CompoundSummary struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// CompoundsNamed collects the compounds with the given name or synonym from the entries
+func CompoundsNamed(entries []Entry, name string) (*CompoundDetails, error) {
+	details := &CompoundDetails{Name: name, Entries: []CompoundOccurrence{}}
+	for i := range entries {
+		data, err := entries[i].Details()
+		if err != nil {
+			return nil, err
+		}
+		for _, compound := range data.Cluster.Compounds {
+			if compound.Matches(name) {
+				details.Entries = append(details.Entries, CompoundOccurrence{Accession: entries[i].Acc, Compound: compound})
+			}
+		}
+	}
+	return details, nil
+}
+
 type RepositoryEntry struct {
 	Accession    string       `json:"accession"`
 	Minimal      bool         `json:"minimal"`
@@ -64,6 +283,56 @@ type ResultStats struct {
 	ClustersByPhylun *LabelsAndCounts `json:"clusters_by_phylun"`
 }
 
+// CrossTabDimensions lists the entry properties statistics can be cross-tabulated by
+var CrossTabDimensions = []string{"class", "phylum", "genus", "completeness", "minimal", "evidence", "activity"}
+
+type CrossTabCell struct {
+	Row    string
+	Column string
+	Count  int
+}
+
+// CrossTab is a matrix of entry counts, Data[i][j] counting the entries matching Rows[i] and Columns[j]
+type CrossTab struct {
+	Rows    []string `json:"rows"`
+	Columns []string `json:"columns"`
+	Data    [][]int  `json:"data"`
+}
+
+// BuildCrossTab arranges the cells into a matrix with sorted row and column labels
+func BuildCrossTab(cells []CrossTabCell) *CrossTab {
+	rowIndex := make(map[string]int)
+	columnIndex := make(map[string]int)
+	table := &CrossTab{Rows: []string{}, Columns: []string{}, Data: [][]int{}}
+	for _, cell := range cells {
+		if _, ok := rowIndex[cell.Row]; !ok {
+			rowIndex[cell.Row] = 0
+			table.Rows = append(table.Rows, cell.Row)
+		}
+		if _, ok := columnIndex[cell.Column]; !ok {
+			columnIndex[cell.Column] = 0
+			table.Columns = append(table.Columns, cell.Column)
+		}
+	}
+
+	sort.Strings(table.Rows)
+	sort.Strings(table.Columns)
+	for i, row := range table.Rows {
+		rowIndex[row] = i
+	}
+	for i, column := range table.Columns {
+		columnIndex[column] = i
+	}
+
+	for range table.Rows {
+		table.Data = append(table.Data, make([]int, len(table.Columns)))
+	}
+	for _, cell := range cells {
+		table.Data[rowIndex[cell.Row]][columnIndex[cell.Column]] += cell.Count
+	}
+	return table
+}
+
 type AccessionRequestLocus struct {
 	GenBankAccession string `json:"genbank_accession"`
 	Start            int    `json:"start"`
@@ -80,14 +349,44 @@ type AccessionRequest struct {
 type MibigModel interface {
 	Counts() (*StatCounts, error)
 	ClusterStats() ([]StatCluster, error)
+	TypeTree() ([]*BgcType, error)
 	GenusStats() ([]TaxonStats, error)
 	Repository() ([]RepositoryEntry, error)
 	Search(t queries.QueryTerm) ([]int, error)
 	Get(ids []int) ([]RepositoryEntry, error)
+	GetEntry(accession string) (*Entry, error)
+	Entries(ids []int) ([]Entry, error)
+	ReleaseHistory() ([]*ReleaseStats, error)
+	EntryIds(accessions []string) ([]int, error)
+	TaxonCounts(ids []int) ([]TaxonCount, error)
+	TaxonomyNode(rank string, name string) (*TaxonomyNode, error)
+	Compound(name string) (*CompoundDetails, error)
+	Compounds(offset int, limit int) ([]CompoundSummary, int, error)
 	Available(category string, term string) ([]AvailableTerm, error)
 	ResultStats(ids []int) (*ResultStats, error)
+	CrossTab(ids []int, rows string, columns string) (*CrossTab, error)
 	GuessCategories(query *queries.Query) error
 	LookupContributors(ids []string) ([]Contributor, error)
+	ContributorProfile(id string) (*ContributorProfile, error)
+	Contributions(userId string) ([]Contribution, error)
+}
+
+type Publication struct {
+	Id      string   `json:"id"`
+	Title   string   `json:"title"`
+	Authors []string `json:"authors"`
+	Journal string   `json:"journal"`
+	Year    int      `json:"year"`
+	Volume  string   `json:"volume"`
+	Issue   string   `json:"issue"`
+	Pages   string   `json:"pages"`
+	Doi     string   `json:"doi"`
+}
+
+type PublicationModel interface {
+	Get(ids []string) ([]Publication, error)
+	Upsert(publications []Publication) error
+	Missing() ([]string, error)
 }
 
 type AvailableTerm struct {
@@ -99,7 +398,12 @@ var (
 	ErrInvalidCategory    = errors.New("Invalid search category")
 	ErrInvalidCredentials = errors.New("models: invalid credentials")
 	ErrDuplicateEmail     = errors.New("models: duplicate email address")
+	ErrDuplicateRole      = errors.New("models: duplicate role name")
 	ErrNoCredentails      = errors.New("No credentials found")
+	ErrNotFound           = errors.New("models: no matching record found")
+	ErrInvalidRank        = errors.New("models: invalid taxonomic rank")
+	ErrInvalidDimension   = errors.New("models: invalid statistics dimension")
+	ErrInvalidToken       = errors.New("models: invalid or expired token")
 )
 
 type LegacySubmission struct {
@@ -141,23 +445,66 @@ type Contributor struct {
 	Organisation string `json:"organisation"`
 }
 
+type Contribution struct {
+	Accession string   `json:"accession"`
+	Versions  []string `json:"versions"`
+}
+
+type ContributorProfile struct {
+	Contributor
+	Contributions []Contribution `json:"contributions"`
+}
+
+// ContributionsBy lists the entries and changelog versions the user contributed to
+func ContributionsBy(entries []Entry, userId string) ([]Contribution, error) {
+	contributions := []Contribution{}
+	for i := range entries {
+		data, err := entries[i].Details()
+		if err != nil {
+			return nil, err
+		}
+
+		var versions []string
+		for _, change := range data.Changelog {
+			for _, contributor := range change.Contributors {
+				if contributor == userId {
+					versions = append(versions, change.Version)
+					break
+				}
+			}
+		}
+		if len(versions) > 0 {
+			contributions = append(contributions, Contribution{Accession: entries[i].Acc, Versions: versions})
+		}
+	}
+	return contributions, nil
+}
+
 type SubmitterModel interface {
 	Ping() error
 	Insert(submitter *Submitter, password string) error
 	GetRolesById(role_ids []int64) ([]Role, error)
 	GetRolesByName(role_names []string) ([]Role, error)
 	Get(email string, active_only bool) (*Submitter, error)
+	GetById(userId string) (*Submitter, error)
 	Authenticate(email, password string) (*Submitter, error)
 	ChangePassword(userId string, password string) error
 	Update(submitter *Submitter, password string) error
 	List() ([]Submitter, error)
 	Delete(email string) error
+	Anonymise(userId string) error
 }
 
 type RoleModel interface {
 	Ping() error
 	List() ([]Role, error)
+	// Get returns ErrNotFound for unknown roles
+	Get(name string) (*Role, error)
+	// Add creates a role, returning its id or ErrDuplicateRole
+	Add(name, description string) (int, error)
 	UserCount(name string) (int, error)
+	Members(name string) ([]Submitter, error)
+	Delete(name string) error
 }
 
 type Submitter struct {
@@ -170,7 +517,9 @@ type Submitter struct {
 	Public       bool
 	GDPRConsent  bool
 	Active       bool
-	Roles        []Role
+	// EmailVerified is set once the owner of Email confirmed the address, or an admin vouched for it
+	EmailVerified bool
+	Roles         []Role
 }
 
 type Role struct {
@@ -179,6 +528,32 @@ type Role struct {
 	Description string
 }
 
+// RoleHierarchy lists the roles controlling API access, from least to most privileged.
+// Each role is granted everything the roles before it are.
+var RoleHierarchy = []string{"guest", "submitter", "curator", "admin"}
+
+// HasRole reports whether the roles include the required role or one ranked above it
+func HasRole(roles []Role, required string) bool {
+	requiredLevel := -1
+	for i, name := range RoleHierarchy {
+		if name == required {
+			requiredLevel = i
+		}
+	}
+
+	for _, role := range roles {
+		if role.Name == required {
+			return true
+		}
+		for i, name := range RoleHierarchy {
+			if name == role.Name && requiredLevel >= 0 && i >= requiredLevel {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func RolesToStrings(roles []Role) []string {
 	roleNames := make([]string, 0, len(roles))
 	for _, role := range roles {
@@ -189,6 +564,210 @@ func RolesToStrings(roles []Role) []string {
 
 type Token struct {
 	Token   string
-	UserId  int
+	UserId  string
+	Purpose string
 	Expires time.Time
 }
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeTwoFactor         = "two_factor"
+)
+
+type TokenModel interface {
+	Generate(email string, purpose string, lifetime time.Duration) (*Token, error)
+	Validate(token string, purpose string) (string, error)
+	Remove(token string) error
+	// RemoveAll invalidates all tokens issued to a user
+	RemoveAll(userId string) error
+	Expire() error
+}
+
+// Session is a login, identified by the refresh token issued for it
+type Session struct {
+	Id      string
+	UserId  string
+	Created time.Time
+	Expires time.Time
+}
+
+// TwoFactor is a user's TOTP enrolment. Until it is confirmed with a valid code, it isn't used for logins.
+type TwoFactor struct {
+	UserId    string
+	Secret    string
+	Confirmed bool
+	// LastStep is the last TOTP period a code was accepted for, so codes can't be replayed
+	LastStep int64
+}
+
+type TwoFactorModel interface {
+	// Get returns ErrNotFound for users who haven't enrolled
+	Get(userId string) (*TwoFactor, error)
+	// Enrol stores a new, unconfirmed secret, replacing any previous enrolment
+	Enrol(userId string, secret string) error
+	// Confirm activates the enrolment, replacing the user's recovery codes
+	Confirm(userId string, recoveryCodes []string) error
+	// UseStep records a code as used, returning ErrInvalidToken if one of the same or a later period was used before
+	UseStep(userId string, step int64) error
+	// UseRecoveryCode consumes a recovery code, returning ErrInvalidToken for unknown or used codes
+	UseRecoveryCode(userId string, code string) error
+	Disable(userId string) error
+}
+
+// Lockout tracks failed logins of a user. While LockedUntil is in the future, logins are refused.
+type Lockout struct {
+	UserId      string
+	Failures    int
+	LockedUntil time.Time
+}
+
+// Locked reports whether the account is locked at the given time
+func (l *Lockout) Locked(now time.Time) bool {
+	return now.Before(l.LockedUntil)
+}
+
+type LockoutModel interface {
+	// Get returns the lockout state of a user, a zero Lockout if there were no failed logins
+	Get(userId string) (*Lockout, error)
+	// RecordFailure counts a failed login, locking the account for duration once maxFailures is reached
+	RecordFailure(userId string, maxFailures int, duration time.Duration) (*Lockout, error)
+	// Reset clears failed logins and unlocks the account
+	Reset(userId string) error
+}
+
+// ApiKey grants scripted access on behalf of a user, limited to the roles in Scopes.
+// A zero Expires or LastUsed means never.
+type ApiKey struct {
+	Id       string
+	UserId   string
+	Name     string
+	Scopes   []string
+	Created  time.Time
+	Expires  time.Time
+	LastUsed time.Time
+}
+
+type ApiKeyModel interface {
+	// Create stores a new key, returning it along with the secret to hand to the user
+	Create(userId string, name string, scopes []string, expires time.Time) (*ApiKey, string, error)
+	List(userId string) ([]ApiKey, error)
+	// Authenticate returns the key for a secret and records its use, or ErrInvalidToken
+	Authenticate(secret string) (*ApiKey, error)
+	Revoke(userId string, keyId string) error
+	RevokeAll(userId string) error
+}
+
+type SessionModel interface {
+	// Create starts a session for the user, returning it along with its refresh token
+	Create(userId string, lifetime time.Duration) (*Session, string, error)
+	// Refresh exchanges a refresh token for a new one, extending the session
+	Refresh(refreshToken string, lifetime time.Duration) (*Session, string, error)
+	Get(sessionId string) (*Session, error)
+	// List returns the user's unexpired sessions, oldest first
+	List(userId string) ([]Session, error)
+	Revoke(sessionId string) error
+	RevokeAll(userId string) error
+	Expire() error
+}
+
+// Actions recorded in the audit log
+const (
+	AuditUserCreate       = "user.create"
+	AuditUserUpdate       = "user.update"
+	AuditUserRoles        = "user.roles"
+	AuditUserActivate     = "user.activate"
+	AuditUserDeactivate   = "user.deactivate"
+	AuditUserDelete       = "user.delete"
+	AuditUserAnonymise    = "user.anonymise"
+	AuditUserPassword     = "user.password"
+	AuditUserUnlock       = "user.unlock"
+	AuditRoleCreate       = "role.create"
+	AuditRoleDelete       = "role.delete"
+	AuditTwoFactorEnable  = "user.2fa.enable"
+	AuditTwoFactorDisable = "user.2fa.disable"
+	AuditApiKeyCreate     = "apikey.create"
+	AuditApiKeyRevoke     = "apikey.revoke"
+	AuditSubmissionCreate = "submission.create"
+	AuditCacheInvalidate  = "cache.invalidate"
+	AuditPublicationsLoad = "publications.load"
+	AuditTaxaImport       = "taxa.import"
+)
+
+// AuditEntry records a privileged action. Actor is the acting user's id, or "cli:<login>" for
+// the command line. Before and After are JSON snapshots of the target, null where there is none.
+// The log is append-only, so entries must not contain personal data.
+type AuditEntry struct {
+	Id        int64           `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+}
+
+// NewAuditEntry creates an entry, encoding the before and after values as JSON
+func NewAuditEntry(actor, action, target string, before, after interface{}) (*AuditEntry, error) {
+	entry := AuditEntry{Actor: actor, Action: action, Target: target}
+	var err error
+	if entry.Before, err = auditValue(before); err != nil {
+		return nil, err
+	}
+	if entry.After, err = auditValue(after); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// auditValue encodes value as JSON, leaving it empty for nil values, including nil pointers
+func auditValue(value interface{}) (json.RawMessage, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil || string(encoded) == "null" {
+		return nil, err
+	}
+	return encoded, nil
+}
+
+// AuditFilter restricts the entries AuditModel.List returns, zero values match everything
+type AuditFilter struct {
+	Actor  string
+	Action string
+	Target string
+	Since  time.Time
+	Limit  int
+}
+
+// Matches reports whether an entry passes the filter, ignoring the limit
+func (f *AuditFilter) Matches(entry *AuditEntry) bool {
+	return (f.Actor == "" || entry.Actor == f.Actor) &&
+		(f.Action == "" || entry.Action == f.Action) &&
+		(f.Target == "" || entry.Target == f.Target) &&
+		(f.Since.IsZero() || !entry.Timestamp.Before(f.Since))
+}
+
+// AuditModel is append-only, entries can't be changed or removed
+type AuditModel interface {
+	// Record appends an entry, setting its Id and Timestamp
+	Record(entry *AuditEntry) error
+	// List returns matching entries, newest first
+	List(filter AuditFilter) ([]AuditEntry, error)
+}
+
+// SubmitterAudit is what the audit log keeps of a submitter. As entries can't be removed,
+// it holds no personal data, so erasing an account doesn't leave any behind.
+type SubmitterAudit struct {
+	Public      bool     `json:"public"`
+	GDPRConsent bool     `json:"gdpr_consent"`
+	Active      bool     `json:"active"`
+	Roles       []string `json:"roles"`
+}
+
+func (s *Submitter) Audit() *SubmitterAudit {
+	return &SubmitterAudit{
+		Public:      s.Public,
+		GDPRConsent: s.GDPRConsent,
+		Active:      s.Active,
+		Roles:       RolesToStrings(s.Roles),
+	}
+}
@@ -1,5 +1,198 @@
 package models
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
 
 func TestFake(t *testing.T) {}
+
+func TestBuildTypeTree(t *testing.T) {
+	types := []BgcType{
+		{Id: 1, Term: "PKS", Description: "Polyketide", Class: "pks"},
+		{Id: 2, ParentId: 1, Term: "T1PKS", Description: "Type I PKS", Class: "pks"},
+		{Id: 3, ParentId: 1, Term: "T2PKS", Description: "Type II PKS", Class: "pks"},
+		{Id: 4, Term: "NRP", Description: "Nonribosomal peptide", Class: "nrps"},
+	}
+	entryTypes := map[int][]int{
+		10: {2},
+		11: {2, 3},
+		12: {3, 4},
+		13: {1},
+	}
+
+	expected := []*BgcType{
+		{Id: 4, Term: "NRP", Description: "Nonribosomal peptide", Class: "nrps", Count: 1},
+		{Id: 1, Term: "PKS", Description: "Polyketide", Class: "pks", Count: 4, Children: []*BgcType{
+			{Id: 2, ParentId: 1, Term: "T1PKS", Description: "Type I PKS", Class: "pks", Count: 2},
+			{Id: 3, ParentId: 1, Term: "T2PKS", Description: "Type II PKS", Class: "pks", Count: 2},
+		}},
+	}
+
+	tree := BuildTypeTree(types, entryTypes)
+	if !cmp.Equal(expected, tree) {
+		t.Errorf("Unexpected tree:\n%s", cmp.Diff(expected, tree))
+	}
+}
+
+func historyEntry(acc string, classes []interface{}, completeness, status string, versions ...string) Entry {
+	var changelog []interface{}
+	for _, version := range versions {
+		changelog = append(changelog, map[string]interface{}{"version": version})
+	}
+	return Entry{Acc: acc, Data: JsonData{
+		"cluster": map[string]interface{}{
+			"biosyn_class": classes,
+			"loci":         map[string]interface{}{"completeness": completeness},
+			"status":       status,
+		},
+		"changelog": changelog,
+	}}
+}
+
+func TestReleaseHistory(t *testing.T) {
+	entries := []Entry{
+		historyEntry("BGC0000001", []interface{}{"NRP"}, "complete", "active", "1.0", "1.4", "1.4", "2.0"),
+		historyEntry("BGC0000002", []interface{}{"NRP", "Polyketide"}, "", "active", "1.10"),
+		historyEntry("BGC0000003", []interface{}{"Polyketide"}, "incomplete", "retired", "1.0", "2.0"),
+		historyEntry("BGC0000004", []interface{}{"RiPP"}, "complete", "active", "2.0", "2.1"),
+	}
+	// Entries migrated from 1.4 without their older changelog aren't new in 2.0
+	migration := entries[3].Data["changelog"].([]interface{})[0].(map[string]interface{})
+	migration["comments"] = []interface{}{"Migrated from v1.4"}
+
+	expected := []*ReleaseStats{
+		{Version: "1.0", ReleaseCounts: ReleaseCounts{Added: 2},
+			ByClass:        map[string]*ReleaseCounts{"NRP": {Added: 1}, "Polyketide": {Added: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"complete": {Added: 1}, "incomplete": {Added: 1}}},
+		{Version: "1.4", ReleaseCounts: ReleaseCounts{Updated: 1},
+			ByClass:        map[string]*ReleaseCounts{"NRP": {Updated: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"complete": {Updated: 1}}},
+		{Version: "1.10", ReleaseCounts: ReleaseCounts{Added: 1},
+			ByClass:        map[string]*ReleaseCounts{"NRP": {Added: 1}, "Polyketide": {Added: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"unknown": {Added: 1}}},
+		{Version: "2.0", ReleaseCounts: ReleaseCounts{Updated: 1, Retired: 1, Migrated: 1},
+			ByClass:        map[string]*ReleaseCounts{"NRP": {Updated: 1}, "Polyketide": {Retired: 1}, "RiPP": {Migrated: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"complete": {Updated: 1, Migrated: 1}, "incomplete": {Retired: 1}}},
+		{Version: "2.1", ReleaseCounts: ReleaseCounts{Updated: 1},
+			ByClass:        map[string]*ReleaseCounts{"RiPP": {Updated: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"complete": {Updated: 1}}},
+	}
+
+	history, err := ReleaseHistory(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(expected, history) {
+		t.Errorf("Unexpected history:\n%s", cmp.Diff(expected, history))
+	}
+}
+
+func TestReleaseHistoryRetiredInFirstVersion(t *testing.T) {
+	entries := []Entry{
+		historyEntry("BGC0000001", []interface{}{"NRP"}, "complete", "retired", "1.0"),
+	}
+
+	expected := []*ReleaseStats{
+		{Version: "1.0", ReleaseCounts: ReleaseCounts{Retired: 1},
+			ByClass:        map[string]*ReleaseCounts{"NRP": {Retired: 1}},
+			ByCompleteness: map[string]*ReleaseCounts{"complete": {Retired: 1}}},
+	}
+
+	history, err := ReleaseHistory(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(expected, history) {
+		t.Errorf("Unexpected history:\n%s", cmp.Diff(expected, history))
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		A, B     string
+		Expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.9", "1.10", -1},
+		{"2.0", "1.4", 1},
+		{"2", "2.0", -1},
+		{"3.0", "3.0a", -1},
+	}
+	for _, tt := range tests {
+		if result := CompareVersions(tt.A, tt.B); result != tt.Expected {
+			t.Errorf("CompareVersions(%q, %q): expected %d, got %d", tt.A, tt.B, tt.Expected, result)
+		}
+	}
+}
+
+func TestBuildCrossTab(t *testing.T) {
+	cells := []CrossTabCell{
+		{Row: "Polyketide", Column: "complete", Count: 3},
+		{Row: "NRP", Column: "incomplete", Count: 2},
+		{Row: "NRP", Column: "complete", Count: 1},
+	}
+
+	expected := &CrossTab{
+		Rows:    []string{"NRP", "Polyketide"},
+		Columns: []string{"complete", "incomplete"},
+		Data:    [][]int{{1, 2}, {3, 0}},
+	}
+
+	table := BuildCrossTab(cells)
+	if !cmp.Equal(expected, table) {
+		t.Errorf("Unexpected table:\n%s", cmp.Diff(expected, table))
+	}
+
+	empty := BuildCrossTab(nil)
+	if len(empty.Rows) != 0 || len(empty.Columns) != 0 || len(empty.Data) != 0 {
+		t.Errorf("Expected empty table, got %v", empty)
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Roles    []string
+		Required string
+		Expected bool
+	}{
+		{"no roles", nil, "guest", false},
+		{"same role", []string{"submitter"}, "submitter", true},
+		{"higher role", []string{"admin"}, "curator", true},
+		{"lower role", []string{"guest", "submitter"}, "curator", false},
+		{"role outside the hierarchy", []string{"reviewer"}, "reviewer", true},
+		{"unknown required role", []string{"admin"}, "reviewer", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var roles []Role
+			for _, name := range tt.Roles {
+				roles = append(roles, Role{Name: name})
+			}
+			if actual := HasRole(roles, tt.Required); actual != tt.Expected {
+				t.Errorf("Expected %t, got %t", tt.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewAuditEntry(t *testing.T) {
+	var missing *Taxon
+	user := Submitter{Id: "AAAA", Email: "alice@example.com", Name: "Alice", Institution: "Testing", PasswordHash: []byte("hash"), Active: true, Roles: []Role{{Name: "curator"}}}
+
+	entry, err := NewAuditEntry("BBBB", AuditUserRoles, user.Id, missing, user.Audit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Before != nil {
+		t.Errorf("Expected no before value for nil pointer, got %s", entry.Before)
+	}
+
+	expected := `{"public":false,"gdpr_consent":false,"active":true,"roles":["curator"]}`
+	if string(entry.After) != expected {
+		t.Errorf("Unexpected after value:\n%s", cmp.Diff(expected, string(entry.After)))
+	}
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+type ApiKeyModel struct {
+	DB *sql.DB
+}
+
+func nullTime(t time.Time) pq.NullTime {
+	return pq.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (m *ApiKeyModel) Create(userId string, name string, scopes []string, expires time.Time) (*models.ApiKey, string, error) {
+	keyId, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, secretHash, err := newSecret(keyId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := models.ApiKey{
+		Id:      keyId,
+		UserId:  userId,
+		Name:    name,
+		Scopes:  scopes,
+		Created: time.Now(),
+		Expires: expires,
+	}
+
+	statement := `INSERT INTO mibig_submitters.apikeys (key_id, user_id, name, secret_hash, scopes, created, expires)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = m.DB.Exec(statement, key.Id, key.UserId, key.Name, secretHash, pq.Array(key.Scopes), key.Created, nullTime(key.Expires))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &key, secret, nil
+}
+
+func (m *ApiKeyModel) List(userId string) ([]models.ApiKey, error) {
+	var keys []models.ApiKey
+	statement := `SELECT key_id, user_id, name, scopes, created, expires, last_used FROM mibig_submitters.apikeys
+	WHERE user_id = $1 ORDER BY created`
+	rows, err := m.DB.Query(statement, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key models.ApiKey
+		var expires, lastUsed pq.NullTime
+		err = rows.Scan(&key.Id, &key.UserId, &key.Name, pq.Array(&key.Scopes), &key.Created, &expires, &lastUsed)
+		if err != nil {
+			return nil, err
+		}
+		key.Expires = expires.Time
+		key.LastUsed = lastUsed.Time
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (m *ApiKeyModel) Authenticate(secret string) (*models.ApiKey, error) {
+	parts := strings.SplitN(secret, ".", 2)
+	if len(parts) != 2 {
+		return nil, models.ErrInvalidToken
+	}
+
+	var key models.ApiKey
+	var storedHash string
+	var expires pq.NullTime
+	statement := `SELECT key_id, user_id, name, secret_hash, scopes, created, expires FROM mibig_submitters.apikeys
+	WHERE key_id = $1 AND (expires IS NULL OR expires > now())`
+	err := m.DB.QueryRow(statement, parts[0]).Scan(&key.Id, &key.UserId, &key.Name, &storedHash, pq.Array(&key.Scopes), &key.Created, &expires)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidToken
+		}
+		return nil, err
+	}
+	key.Expires = expires.Time
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashToken(parts[1]))) != 1 {
+		return nil, models.ErrInvalidToken
+	}
+
+	key.LastUsed = time.Now()
+	_, err = m.DB.Exec(`UPDATE mibig_submitters.apikeys SET last_used = $1 WHERE key_id = $2`, key.LastUsed, key.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Revoke deletes one of the user's keys, returning ErrNotFound if the user has no such key
+func (m *ApiKeyModel) Revoke(userId string, keyId string) error {
+	result, err := m.DB.Exec(`DELETE FROM mibig_submitters.apikeys WHERE user_id = $1 AND key_id = $2`, userId, keyId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *ApiKeyModel) RevokeAll(userId string) error {
+	_, err := m.DB.Exec(`DELETE FROM mibig_submitters.apikeys WHERE user_id = $1`, userId)
+	return err
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type AuditModel struct {
+	DB *sql.DB
+}
+
+// nullJSON turns empty JSON values into SQL NULL
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
+
+func (m *AuditModel) Record(entry *models.AuditEntry) error {
+	statement := `INSERT INTO mibig_submitters.audit_log (actor, action, target, before, after)
+	VALUES ($1, $2, $3, $4, $5) RETURNING audit_id, created`
+	return m.DB.QueryRow(statement, entry.Actor, entry.Action, entry.Target, nullJSON(entry.Before), nullJSON(entry.After)).
+		Scan(&entry.Id, &entry.Timestamp)
+}
+
+func (m *AuditModel) List(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+
+	since := pq.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()}
+	var limit interface{}
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	statement := `SELECT audit_id, created, actor, action, target, before, after FROM mibig_submitters.audit_log
+	WHERE ($1 = '' OR actor = $1)
+	AND ($2 = '' OR action = $2)
+	AND ($3 = '' OR target = $3)
+	AND ($4::timestamptz IS NULL OR created >= $4)
+	ORDER BY audit_id DESC
+	LIMIT $5`
+	rows, err := m.DB.Query(statement, filter.Actor, filter.Action, filter.Target, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after []byte
+		err = rows.Scan(&entry.Id, &entry.Timestamp, &entry.Actor, &entry.Action, &entry.Target, &before, &after)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
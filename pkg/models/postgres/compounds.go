@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// Compound returns all occurrences of the compound with the given name or synonym
+func (m *MibigModel) Compound(name string) (*models.CompoundDetails, error) {
+	statement := `SELECT
+		entry_id, acc, tax_id, data, ` + taxonColumns + `
+	FROM mibig.entries
+	LEFT JOIN mibig.taxa USING (tax_id)
+	WHERE entry_id IN (
+		SELECT entry_id FROM mibig.compounds WHERE lower(name) = lower($1)
+		UNION
+		SELECT entry_id FROM mibig.entries,
+			jsonb_array_elements(data#>'{cluster, compounds}') AS compound,
+			jsonb_array_elements_text(compound->'chem_synonyms') AS synonym
+		WHERE lower(synonym) = lower($1)
+	)
+	ORDER BY acc`
+
+	rows, err := m.DB.Query(statement, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := parseEntriesFromDB(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := models.CompoundsNamed(entries, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(details.Entries) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return details, nil
+}
+
+// Compounds lists the compound names with the number of entries producing them, ordered by name.
+// A limit of 0 returns all compounds from the offset on. The total number of compounds is returned as well.
+func (m *MibigModel) Compounds(offset int, limit int) ([]models.CompoundSummary, int, error) {
+	var total int
+	err := m.DB.QueryRow(`SELECT COUNT(DISTINCT name) FROM mibig.compounds`).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statement := `SELECT name, COUNT(DISTINCT entry_id)
+	FROM mibig.compounds
+	GROUP BY name
+	ORDER BY lower(name), name
+	OFFSET $1
+	LIMIT $2`
+
+	var limit_value interface{}
+	if limit > 0 {
+		limit_value = limit
+	}
+
+	rows, err := m.DB.Query(statement, offset, limit_value)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	compounds := []models.CompoundSummary{}
+	for rows.Next() {
+		var compound models.CompoundSummary
+		if err = rows.Scan(&compound.Name, &compound.Count); err != nil {
+			return nil, 0, err
+		}
+		compounds = append(compounds, compound)
+	}
+	return compounds, total, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// dimensionExpressions are LATERAL subqueries over the entry e, returning one row per value
+var dimensionExpressions = map[string]string{
+	"class":        `SELECT jsonb_array_elements_text(e.data#>'{cluster, biosyn_class}')`,
+	"phylum":       `SELECT phylum FROM mibig.taxa WHERE tax_id = e.tax_id`,
+	"genus":        `SELECT genus FROM mibig.taxa WHERE tax_id = e.tax_id`,
+	"completeness": `SELECT e.data#>>'{cluster, loci, completeness}'`,
+	"minimal":      `SELECT e.data#>>'{cluster, minimal}'`,
+	"evidence":     `SELECT DISTINCT jsonb_array_elements_text(e.data#>'{cluster, loci, evidence}')`,
+	"activity": `SELECT DISTINCT jsonb_array_elements_text(compound->'chem_acts')
+		FROM jsonb_array_elements(e.data#>'{cluster, compounds}') AS compound`,
+}
+
+// CrossTab counts the given entries, or all entries if ids is nil, by two dimensions.
+// Entries without a value for a dimension are counted as "unknown".
+func (m *MibigModel) CrossTab(ids []int, rows string, columns string) (*models.CrossTab, error) {
+	rowExpression, ok := dimensionExpressions[rows]
+	if !ok {
+		return nil, models.ErrInvalidDimension
+	}
+	columnExpression, ok := dimensionExpressions[columns]
+	if !ok {
+		return nil, models.ErrInvalidDimension
+	}
+
+	statement := fmt.Sprintf(`SELECT COALESCE(r.value, 'unknown'), COALESCE(c.value, 'unknown'), COUNT(DISTINCT e.entry_id)
+	FROM mibig.entries e
+	LEFT JOIN LATERAL (%s) AS r(value) ON TRUE
+	LEFT JOIN LATERAL (%s) AS c(value) ON TRUE
+	WHERE $1::int[] IS NULL OR e.entry_id = ANY($1::int[])
+	GROUP BY 1, 2`, rowExpression, columnExpression)
+
+	var id_array interface{}
+	if ids != nil {
+		id_array = pq.Array(ids)
+	}
+
+	result, err := m.DB.Query(statement, id_array)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	var cells []models.CrossTabCell
+	for result.Next() {
+		var cell models.CrossTabCell
+		if err = result.Scan(&cell.Row, &cell.Column, &cell.Count); err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	if err = result.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.BuildCrossTab(cells), nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type LockoutModel struct {
+	DB *sql.DB
+}
+
+func (m *LockoutModel) Get(userId string) (*models.Lockout, error) {
+	lockout := models.Lockout{UserId: userId}
+	var lockedUntil pq.NullTime
+	statement := `SELECT failures, locked_until FROM mibig_submitters.lockouts WHERE user_id = $1`
+	err := m.DB.QueryRow(statement, userId).Scan(&lockout.Failures, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &lockout, nil
+		}
+		return nil, err
+	}
+	lockout.LockedUntil = lockedUntil.Time
+	return &lockout, nil
+}
+
+// RecordFailure counts a failed login. Reaching maxFailures locks the account and starts counting anew.
+func (m *LockoutModel) RecordFailure(userId string, maxFailures int, duration time.Duration) (*models.Lockout, error) {
+	lockout := models.Lockout{UserId: userId}
+	var lockedUntil pq.NullTime
+	statement := `INSERT INTO mibig_submitters.lockouts AS l (user_id, failures) VALUES ($1, 1)
+	ON CONFLICT (user_id) DO UPDATE SET failures = l.failures + 1
+	RETURNING failures, locked_until`
+	err := m.DB.QueryRow(statement, userId).Scan(&lockout.Failures, &lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	lockout.LockedUntil = lockedUntil.Time
+
+	if lockout.Failures < maxFailures {
+		return &lockout, nil
+	}
+
+	lockout.Failures = 0
+	lockout.LockedUntil = time.Now().Add(duration)
+	_, err = m.DB.Exec(`UPDATE mibig_submitters.lockouts SET failures = 0, locked_until = $1 WHERE user_id = $2`,
+		lockout.LockedUntil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lockout, nil
+}
+
+func (m *LockoutModel) Reset(userId string) error {
+	_, err := m.DB.Exec(`DELETE FROM mibig_submitters.lockouts WHERE user_id = $1`, userId)
+	return err
+}
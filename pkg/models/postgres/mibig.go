@@ -182,6 +182,106 @@ func (m *MibigModel) Get(ids []int) ([]models.RepositoryEntry, error) {
 	return parseRepositoryEntriesFromDB(rows)
 }
 
+func (m *MibigModel) GetEntry(accession string) (*models.Entry, error) {
+	statement := `SELECT
+		entry_id, acc, tax_id, data, ` + taxonColumns + `
+	FROM mibig.entries
+	LEFT JOIN mibig.taxa USING (tax_id)
+	WHERE acc = $1`
+
+	rows, err := m.DB.Query(statement, accession)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := parseEntriesFromDB(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return &entries[0], nil
+}
+
+// Entries returns the full entries for the given ids, or all entries if ids is nil
+func (m *MibigModel) Entries(ids []int) ([]models.Entry, error) {
+	statement := `SELECT
+		entry_id, acc, tax_id, data, ` + taxonColumns + `
+	FROM mibig.entries
+	LEFT JOIN mibig.taxa USING (tax_id)
+	WHERE $1::int[] IS NULL OR entry_id = ANY($1::int[])
+	ORDER BY acc`
+
+	var id_array interface{}
+	if ids != nil {
+		id_array = pq.Array(ids)
+	}
+
+	rows, err := m.DB.Query(statement, id_array)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return parseEntriesFromDB(rows)
+}
+
+// ReleaseHistory summarises the changes per release, based on the changelogs of all entries
+func (m *MibigModel) ReleaseHistory() ([]*models.ReleaseStats, error) {
+	entries, err := m.Entries(nil)
+	if err != nil {
+		return nil, err
+	}
+	return models.ReleaseHistory(entries)
+}
+
+func (m *MibigModel) EntryIds(accessions []string) ([]int, error) {
+	statement := `SELECT entry_id FROM mibig.entries WHERE acc = ANY($1::text[]) ORDER BY acc`
+
+	rows, err := m.DB.Query(statement, pq.Array(accessions))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entry_ids []int
+	for rows.Next() {
+		var entry_id int
+		if err = rows.Scan(&entry_id); err != nil {
+			return nil, err
+		}
+		entry_ids = append(entry_ids, entry_id)
+	}
+	return entry_ids, nil
+}
+
+func parseEntriesFromDB(rows *sql.Rows) ([]models.Entry, error) {
+	var entries []models.Entry
+
+	for rows.Next() {
+		var raw_data []byte
+		var taxon nullTaxon
+
+		entry := models.Entry{}
+		targets := append([]interface{}{&entry.ID, &entry.Acc, &entry.TaxID, &raw_data}, taxon.targets()...)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(raw_data, &entry.Data); err != nil {
+			return nil, err
+		}
+
+		entry.Taxon = taxon.toTaxon(entry.TaxID)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 var categoryDetector = map[string]string{
 	"type":     `SELECT COUNT(bgc_type_id) FROM mibig.bgc_types WHERE term ILIKE $1`,
 	"acc":      `SELECT COUNT(entry_id) FROM mibig.entries WHERE acc ILIKE $1`,
@@ -451,3 +551,46 @@ func (m *MibigModel) LookupContributors(ids []string) ([]models.Contributor, err
 	}
 	return contributors, nil
 }
+
+// ContributorProfile returns the public profile of a contributor with the entries they contributed to.
+// Only submitters who are public and gave GDPR consent are returned, like in LookupContributors.
+func (m *MibigModel) ContributorProfile(id string) (*models.ContributorProfile, error) {
+	contributors, err := m.LookupContributors([]string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(contributors) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	contributions, err := m.Contributions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ContributorProfile{Contributor: contributors[0], Contributions: contributions}, nil
+}
+
+// Contributions lists the entries and changelog versions the user contributed to,
+// regardless of whether their profile is public
+func (m *MibigModel) Contributions(userId string) ([]models.Contribution, error) {
+	statement := `SELECT
+		entry_id, acc, tax_id, data, ` + taxonColumns + `
+	FROM mibig.entries
+	LEFT JOIN mibig.taxa USING (tax_id)
+	WHERE data->'changelog' @> jsonb_build_array(jsonb_build_object('contributors', jsonb_build_array($1::text)))
+	ORDER BY acc`
+
+	rows, err := m.DB.Query(statement, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := parseEntriesFromDB(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ContributionsBy(entries, userId)
+}
@@ -60,10 +60,13 @@ func TestMibigModel(t *testing.T) {
 
 	t.Run("Counts", mt.MibigModelCounts)
 	t.Run("ClusterStats", mt.MibigModelClusterStats)
+	t.Run("TypeTree", mt.MibigModelTypeTree)
 	t.Run("Repository", mt.MibigModelRepository)
 	t.Run("Get", mt.MibigModelGet)
+	t.Run("GetEntry", mt.MibigModelGetEntry)
 	t.Run("Search", mt.MibigModelSearch)
 	t.Run("Available", mt.MibigModelAvailable)
+	t.Run("ContributorProfile", mt.MibigModelContributorProfile)
 
 }
 
@@ -95,6 +98,43 @@ func (mt *MibigModelTest) MibigModelClusterStats(t *testing.T) {
 	}
 }
 
+func (mt *MibigModelTest) MibigModelTypeTree(t *testing.T) {
+	tree, err := mt.m.TypeTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// BGC0001070 has two polyketide subtypes, but is only counted once for pks
+	expected := map[string]int{"nrps": 1, "pks": 1, "ripp": 1}
+	for term, count := range expected {
+		node := findType(tree, term)
+		if node == nil {
+			t.Errorf("TypeTree missing %q", term)
+			continue
+		}
+		if node.Count != count {
+			t.Errorf("TypeTree %q: want %d, got %d", term, count, node.Count)
+		}
+	}
+
+	pks := findType(tree, "pks")
+	if pks != nil && len(pks.Children) == 0 {
+		t.Errorf("TypeTree expected subtypes for pks")
+	}
+}
+
+func findType(types []*models.BgcType, term string) *models.BgcType {
+	for _, node := range types {
+		if node.Term == term {
+			return node
+		}
+		if found := findType(node.Children, term); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 func (mt *MibigModelTest) MibigModelRepository(t *testing.T) {
 	expected := []models.RepositoryEntry{
 		{Accession: "BGC0000535", Complete: "complete", Minimal: false, Products: []string{"nisin A"}, ProductTags: []models.ProductTag{{Name: "Lanthipeptide", Class: "ripp"}}, OrganismName: "Lactococcus lactis subsp. lactis"},
@@ -146,6 +186,29 @@ func (mt *MibigModelTest) MibigModelGet(t *testing.T) {
 	}
 }
 
+func (mt *MibigModelTest) MibigModelGetEntry(t *testing.T) {
+	entry, err := mt.m.GetEntry("BGC0000535")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.ID != 535 || entry.TaxID != 1360 {
+		t.Errorf("GetEntry unexpected entry: %v", entry)
+	}
+
+	expectedTaxon := &models.Taxon{
+		TaxID: 1360, Superkingdom: "Bacteria", Phylum: "Firmicutes", Class: "Bacilli", Order: "Lactobacillales",
+		Family: "Streptococcaceae", Genus: "Lactococcus", Species: "lactis", Name: "Lactococcus lactis subsp. lactis",
+	}
+	if !cmp.Equal(expectedTaxon, entry.Taxon) {
+		t.Errorf("GetEntry unexpected taxon:\n%s", cmp.Diff(expectedTaxon, entry.Taxon))
+	}
+
+	if _, err = mt.m.GetEntry("BGC9999999"); err != models.ErrNotFound {
+		t.Errorf("GetEntry unexpected error: want %v, got %v", models.ErrNotFound, err)
+	}
+}
+
 func (mt *MibigModelTest) MibigModelSearch(t *testing.T) {
 	tests := []struct {
 		Name           string
@@ -204,3 +267,31 @@ func (mt *MibigModelTest) MibigModelAvailable(t *testing.T) {
 		})
 	}
 }
+
+func (mt *MibigModelTest) MibigModelContributorProfile(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Id            string
+		Expected      *models.ContributorProfile
+		ExpectedError error
+	}{
+		{Name: "Public", Id: "AAAAAAAAAAAAAAAAAAAAAAAB", Expected: &models.ContributorProfile{
+			Contributor:   models.Contributor{Id: "AAAAAAAAAAAAAAAAAAAAAAAB", Name: "Alice User", Email: "alice@example.org", Organisation: "Testing"},
+			Contributions: []models.Contribution{{Accession: "BGC0001070", Versions: []string{"2.1"}}},
+		}},
+		{Name: "No consent", Id: "AAAAAAAAAAAAAAAAAAAAAAAC", ExpectedError: models.ErrNotFound},
+		{Name: "Not public", Id: "AAAAAAAAAAAAAAAAAAAAAAAD", ExpectedError: models.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			profile, err := mt.m.ContributorProfile(tt.Id)
+			if err != tt.ExpectedError {
+				t.Fatalf("Unexpected error, want %v, got %v", tt.ExpectedError, err)
+			}
+			if !cmp.Equal(tt.Expected, profile) {
+				t.Errorf("ContributorProfile unexpected results:\n%s", cmp.Diff(tt.Expected, profile))
+			}
+		})
+	}
+}
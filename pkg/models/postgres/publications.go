@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type PublicationModel struct {
+	DB *sql.DB
+}
+
+func (m *PublicationModel) Get(ids []string) ([]models.Publication, error) {
+	statement := `SELECT pub_id, title, authors, journal, year, volume, issue, pages, doi
+	FROM mibig.publications
+	WHERE pub_id = ANY($1::text[])
+	ORDER BY pub_id`
+
+	rows, err := m.DB.Query(statement, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var publications []models.Publication
+	for rows.Next() {
+		var publication models.Publication
+		err = rows.Scan(&publication.Id, &publication.Title, pq.Array(&publication.Authors), &publication.Journal,
+			&publication.Year, &publication.Volume, &publication.Issue, &publication.Pages, &publication.Doi)
+		if err != nil {
+			return nil, err
+		}
+		publications = append(publications, publication)
+	}
+	return publications, nil
+}
+
+func (m *PublicationModel) Upsert(publications []models.Publication) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	statement := `INSERT INTO mibig.publications
+(pub_id, title, authors, journal, year, volume, issue, pages, doi)
+VALUES
+($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (pub_id) DO UPDATE SET
+title = EXCLUDED.title, authors = EXCLUDED.authors, journal = EXCLUDED.journal, year = EXCLUDED.year,
+volume = EXCLUDED.volume, issue = EXCLUDED.issue, pages = EXCLUDED.pages, doi = EXCLUDED.doi`
+
+	for _, p := range publications {
+		_, err = tx.Exec(statement, p.Id, p.Title, pq.Array(p.Authors), p.Journal, p.Year, p.Volume, p.Issue, p.Pages, p.Doi)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Missing lists the publications referenced by entries that are not in the local cache yet
+func (m *PublicationModel) Missing() ([]string, error) {
+	statement := `SELECT DISTINCT jsonb_array_elements_text(data#>'{cluster, publications}') AS pub_id FROM mibig.entries
+	EXCEPT
+	SELECT pub_id FROM mibig.publications
+	ORDER BY pub_id`
+
+	rows, err := m.DB.Query(statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var pub_id string
+		if err = rows.Scan(&pub_id); err != nil {
+			return nil, err
+		}
+		missing = append(missing, pub_id)
+	}
+	return missing, nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/lib/pq"
+
 	"secondarymetabolites.org/mibig-api/pkg/models"
 )
 
@@ -17,13 +19,9 @@ func (m *RoleModel) Ping() error {
 
 func (m *RoleModel) List() ([]models.Role, error) {
 	var roles []models.Role
-	statement := `SELECT role_id, name, description FROM mibig_submitters.roles`
+	statement := `SELECT role_id, name, description FROM mibig_submitters.roles ORDER BY role_id`
 	rows, err := m.DB.Query(statement)
 	if err != nil {
-		// No roles is not an error in this context
-		if errors.Is(err, sql.ErrNoRows) {
-			return roles, nil
-		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -36,28 +34,44 @@ func (m *RoleModel) List() ([]models.Role, error) {
 		}
 		roles = append(roles, role)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return roles, nil
 }
 
-func (m *RoleModel) Add(name, description string) (int, error) {
-	statement := `INSERT INTO mibig_submitters.roles (name, description) VALUES (?, ?)`
-	ret, err := m.DB.Exec(statement, name, description)
+func (m *RoleModel) Get(name string) (*models.Role, error) {
+	role := models.Role{Name: name}
+	statement := `SELECT role_id, description FROM mibig_submitters.roles WHERE name = $1`
+	err := m.DB.QueryRow(statement, name).Scan(&role.Id, &role.Description)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
 	}
+	return &role, nil
+}
 
-	roleId, err := ret.LastInsertId()
+func (m *RoleModel) Add(name, description string) (int, error) {
+	var roleId int
+	statement := `INSERT INTO mibig_submitters.roles (name, description) VALUES ($1, $2) RETURNING role_id`
+	err := m.DB.QueryRow(statement, name, description).Scan(&roleId)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, models.ErrDuplicateRole
+		}
 		return 0, err
 	}
-	return int(roleId), nil
+	return roleId, nil
 }
 
 func (m *RoleModel) UserCount(name string) (int, error) {
 	var count int
-	statement := `SELECT COUNT(role_id) FROM mibig_submitters.rel_submitters_roles LEFT JOIN mibig_submitters.roles USING (role_id)
-	WHERE name = ?`
+	statement := `SELECT COUNT(user_id) FROM mibig_submitters.rel_submitters_roles JOIN mibig_submitters.roles USING (role_id)
+	WHERE name = $1`
 	row := m.DB.QueryRow(statement, name)
 	err := row.Scan(&count)
 	if err != nil {
@@ -67,6 +81,36 @@ func (m *RoleModel) UserCount(name string) (int, error) {
 	return count, nil
 }
 
+// Members lists the users holding a role, without their roles
+func (m *RoleModel) Members(name string) ([]models.Submitter, error) {
+	var members []models.Submitter
+	statement := `SELECT user_id, email, name, active FROM mibig_submitters.submitters
+	JOIN mibig_submitters.rel_submitters_roles USING (user_id)
+	JOIN mibig_submitters.roles r USING (role_id)
+	WHERE r.name = $1
+	ORDER BY email`
+	rows, err := m.DB.Query(statement, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member models.Submitter
+		err = rows.Scan(&member.Id, &member.Email, &member.Name, &member.Active)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Delete removes a role, taking it away from all users holding it
 func (m *RoleModel) Delete(name string) error {
 	var roleId int
 
@@ -75,20 +119,23 @@ func (m *RoleModel) Delete(name string) error {
 		return err
 	}
 
-	row := tx.QueryRow(`SELECT role_id FROM mibig_submitters.roles WHERE name = ?`, name)
+	row := tx.QueryRow(`SELECT role_id FROM mibig_submitters.roles WHERE name = $1`, name)
 	err = row.Scan(&roleId)
 	if err != nil {
 		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrNotFound
+		}
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM mibig_submitters.rel_submitters_roles WHERE role_id = ?`, roleId)
+	_, err = tx.Exec(`DELETE FROM mibig_submitters.rel_submitters_roles WHERE role_id = $1`, roleId)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM mibig_submitters.roles WHERE role_id = ?`, roleId)
+	_, err = tx.Exec(`DELETE FROM mibig_submitters.roles WHERE role_id = $1`, roleId)
 	if err != nil {
 		tx.Rollback()
 		return err
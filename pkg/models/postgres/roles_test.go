@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type RoleModelTest struct {
+	m        *RoleModel
+	Teardown func()
+}
+
+func newRoleTestDB(t *testing.T) *RoleModelTest {
+	mt := RoleModelTest{}
+
+	db, err := sql.Open("postgres", "host=localhost port=5432 user=postgres password=secret dbname=mibig_test sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := ioutil.ReadFile("./testdata/setup.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(string(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mt.m = &RoleModel{DB: db}
+
+	mt.Teardown = func() {
+		script, err := ioutil.ReadFile("./testdata/teardown.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = db.Exec(string(script))
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.Close()
+	}
+	return &mt
+}
+
+func TestRoleModel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("postgres: skipping integration test")
+	}
+
+	mt := newRoleTestDB(t)
+	defer mt.Teardown()
+
+	t.Run("List", mt.List)
+	t.Run("Get", mt.Get)
+	t.Run("Members", mt.Members)
+	t.Run("AddDelete", mt.AddDelete)
+}
+
+func (mt *RoleModelTest) List(t *testing.T) {
+	expected := []string{"admin", "curator", "submitter", "guest"}
+
+	roles, err := mt.m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := models.RolesToStrings(roles); !cmp.Equal(expected, names) {
+		t.Errorf("List unexpected results:\n%s", cmp.Diff(expected, names))
+	}
+}
+
+func (mt *RoleModelTest) Get(t *testing.T) {
+	expected := &models.Role{Id: 2, Name: "curator", Description: "Users who can approve new entries"}
+
+	role, err := mt.m.Get("curator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(expected, role) {
+		t.Errorf("Get unexpected result:\n%s", cmp.Diff(expected, role))
+	}
+
+	if _, err = mt.m.Get("nonexistent"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func (mt *RoleModelTest) Members(t *testing.T) {
+	expected := []models.Submitter{
+		{Id: "AAAAAAAAAAAAAAAAAAAAAAAB", Email: "alice@example.org", Name: "Alice User", Active: true},
+	}
+
+	members, err := mt.m.Members("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(expected, members) {
+		t.Errorf("Members unexpected results:\n%s", cmp.Diff(expected, members))
+	}
+
+	count, err := mt.m.UserCount("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 admin, got %d", count)
+	}
+}
+
+func (mt *RoleModelTest) AddDelete(t *testing.T) {
+	roleId, err := mt.m.Add("reviewer", "Users who review submissions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roleId <= 4 {
+		t.Errorf("Expected a new role id, got %d", roleId)
+	}
+
+	if _, err = mt.m.Add("reviewer", "Duplicate"); !errors.Is(err, models.ErrDuplicateRole) {
+		t.Errorf("Expected ErrDuplicateRole, got %v", err)
+	}
+
+	if err = mt.m.Delete("reviewer"); err != nil {
+		t.Fatal(err)
+	}
+	if err = mt.m.Delete("reviewer"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
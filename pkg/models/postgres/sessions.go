@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+type SessionModel struct {
+	DB *sql.DB
+}
+
+// newSecret returns a token of the form <id>.<secret> and the hash of the secret to store
+func newSecret(id string) (string, string, error) {
+	secret, err := utils.GenerateUid(32)
+	if err != nil {
+		return "", "", err
+	}
+	return id + "." + secret, hashToken(secret), nil
+}
+
+func (m *SessionModel) Create(userId string, lifetime time.Duration) (*models.Session, string, error) {
+	sessionId, err := utils.GenerateUid(15)
+	if err != nil {
+		return nil, "", err
+	}
+	refreshToken, secretHash, err := newSecret(sessionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	session := models.Session{
+		Id:      sessionId,
+		UserId:  userId,
+		Created: time.Now(),
+		Expires: time.Now().Add(lifetime),
+	}
+
+	statement := `INSERT INTO mibig_submitters.sessions (session_id, user_id, secret_hash, created, expires) VALUES ($1, $2, $3, $4, $5)`
+	_, err = m.DB.Exec(statement, session.Id, session.UserId, secretHash, session.Created, session.Expires)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &session, refreshToken, nil
+}
+
+// Refresh rotates the session's refresh token. Presenting a token that was already rotated
+// means it was copied, so the whole session is revoked.
+func (m *SessionModel) Refresh(refreshToken string, lifetime time.Duration) (*models.Session, string, error) {
+	parts := strings.SplitN(refreshToken, ".", 2)
+	if len(parts) != 2 {
+		return nil, "", models.ErrInvalidToken
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var session models.Session
+	var storedHash string
+	statement := `SELECT session_id, user_id, secret_hash, created, expires FROM mibig_submitters.sessions
+	WHERE session_id = $1 AND expires > now() FOR UPDATE`
+	err = tx.QueryRow(statement, parts[0]).Scan(&session.Id, &session.UserId, &storedHash, &session.Created, &session.Expires)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", models.ErrInvalidToken
+		}
+		return nil, "", err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashToken(parts[1]))) != 1 {
+		if _, err = tx.Exec(`DELETE FROM mibig_submitters.sessions WHERE session_id = $1`, session.Id); err != nil {
+			tx.Rollback()
+			return nil, "", err
+		}
+		if err = tx.Commit(); err != nil {
+			return nil, "", err
+		}
+		return nil, "", models.ErrInvalidToken
+	}
+
+	newToken, secretHash, err := newSecret(session.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	session.Expires = time.Now().Add(lifetime)
+
+	_, err = tx.Exec(`UPDATE mibig_submitters.sessions SET secret_hash = $1, expires = $2 WHERE session_id = $3`,
+		secretHash, session.Expires, session.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, "", err
+	}
+
+	return &session, newToken, nil
+}
+
+func (m *SessionModel) Get(sessionId string) (*models.Session, error) {
+	var session models.Session
+	statement := `SELECT session_id, user_id, created, expires FROM mibig_submitters.sessions WHERE session_id = $1 AND expires > now()`
+	err := m.DB.QueryRow(statement, sessionId).Scan(&session.Id, &session.UserId, &session.Created, &session.Expires)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (m *SessionModel) List(userId string) ([]models.Session, error) {
+	var sessions []models.Session
+	statement := `SELECT session_id, user_id, created, expires FROM mibig_submitters.sessions
+	WHERE user_id = $1 AND expires > now() ORDER BY created`
+	rows, err := m.DB.Query(statement, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session models.Session
+		if err = rows.Scan(&session.Id, &session.UserId, &session.Created, &session.Expires); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (m *SessionModel) Revoke(sessionId string) error {
+	_, err := m.DB.Exec(`DELETE FROM mibig_submitters.sessions WHERE session_id = $1`, sessionId)
+	return err
+}
+
+func (m *SessionModel) RevokeAll(userId string) error {
+	_, err := m.DB.Exec(`DELETE FROM mibig_submitters.sessions WHERE user_id = $1`, userId)
+	return err
+}
+
+func (m *SessionModel) Expire() error {
+	_, err := m.DB.Exec(`DELETE FROM mibig_submitters.sessions WHERE expires < now()`)
+	return err
+}
@@ -12,6 +12,9 @@ import (
 	"secondarymetabolites.org/mibig-api/pkg/utils"
 )
 
+// uniqueViolation is the Postgres error code for duplicate keys
+const uniqueViolation = "23505"
+
 type SubmitterModel struct {
 	DB            *sql.DB
 	roleIdCache   map[int64]*models.Role
@@ -50,13 +53,18 @@ func (m *SubmitterModel) Insert(submitter *models.Submitter, password string) er
 	}
 
 	statement := `INSERT INTO mibig_submitters.submitters
-(user_id, email, name, call_name, institution, password_hash, is_public, gdpr_consent, active)
+(user_id, email, name, call_name, institution, password_hash, is_public, gdpr_consent, active, email_verified)
 VALUES
-($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 	_, err = tx.Exec(statement, submitter.Id, submitter.Email, submitter.Name, submitter.CallName,
-		submitter.Institution, submitter.PasswordHash, submitter.Public, submitter.GDPRConsent, submitter.Active)
+		submitter.Institution, submitter.PasswordHash, submitter.Public, submitter.GDPRConsent, submitter.Active,
+		submitter.EmailVerified)
 	if err != nil {
 		tx.Rollback()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return models.ErrDuplicateEmail
+		}
 		return err
 	}
 
@@ -119,7 +127,7 @@ func (m *SubmitterModel) GetRolesByName(role_names []string) ([]models.Role, err
 
 func (m *SubmitterModel) Get(email string, active_only bool) (*models.Submitter, error) {
 	var submitter models.Submitter
-	statement := `SELECT u.user_id, u.email, u.name, u.call_name, u.institution, u.password_hash, u.is_public, u.gdpr_consent, u.active, array_agg(role_id) AS role_ids 
+	statement := `SELECT u.user_id, u.email, u.name, u.call_name, u.institution, u.password_hash, u.is_public, u.gdpr_consent, u.active, u.email_verified, array_agg(role_id) AS role_ids 
 FROM mibig_submitters.submitters AS u
 LEFT JOIN mibig_submitters.rel_submitters_roles USING (user_id)
 WHERE u.email = $1`
@@ -132,7 +140,7 @@ WHERE u.email = $1`
 
 	row := m.DB.QueryRow(statement, email)
 	err := row.Scan(&submitter.Id, &submitter.Email, &submitter.Name, &submitter.CallName, &submitter.Institution,
-		&submitter.PasswordHash, &submitter.Public, &submitter.GDPRConsent, &submitter.Active, pq.Array(&role_ids))
+		&submitter.PasswordHash, &submitter.Public, &submitter.GDPRConsent, &submitter.Active, &submitter.EmailVerified, pq.Array(&role_ids))
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +153,45 @@ WHERE u.email = $1`
 	return &submitter, nil
 }
 
+func (m *SubmitterModel) GetById(userId string) (*models.Submitter, error) {
+	var submitter models.Submitter
+	statement := `SELECT u.user_id, u.email, u.name, u.call_name, u.institution, u.password_hash, u.is_public, u.gdpr_consent, u.active, u.email_verified, array_agg(role_id) AS role_ids
+FROM mibig_submitters.submitters AS u
+LEFT JOIN mibig_submitters.rel_submitters_roles USING (user_id)
+WHERE u.user_id = $1
+GROUP BY user_id`
+
+	var role_ids []sql.NullInt64
+
+	row := m.DB.QueryRow(statement, userId)
+	err := row.Scan(&submitter.Id, &submitter.Email, &submitter.Name, &submitter.CallName, &submitter.Institution,
+		&submitter.PasswordHash, &submitter.Public, &submitter.GDPRConsent, &submitter.Active, &submitter.EmailVerified, pq.Array(&role_ids))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	submitter.Roles, err = m.GetRolesById(validRoleIds(role_ids))
+	if err != nil {
+		return nil, err
+	}
+
+	return &submitter, nil
+}
+
+// validRoleIds drops the NULL role id array_agg returns for users without roles
+func validRoleIds(role_ids []sql.NullInt64) []int64 {
+	var ids []int64
+	for _, id := range role_ids {
+		if id.Valid {
+			ids = append(ids, id.Int64)
+		}
+	}
+	return ids
+}
+
 func (m *SubmitterModel) Authenticate(email, password string) (*models.Submitter, error) {
 
 	submitter, err := m.Get(email, true)
@@ -204,10 +251,11 @@ func (m *SubmitterModel) Update(submitter *models.Submitter, password string) er
 	}
 
 	statement := `UPDATE mibig_submitters.submitters SET
-email = $1, name = $2, call_name = $3, password_hash = $4, institution = $5, is_public = $6, gdpr_consent = $7, active = $8
-WHERE user_id = $9`
+email = $1, name = $2, call_name = $3, password_hash = $4, institution = $5, is_public = $6, gdpr_consent = $7, active = $8,
+email_verified = $9
+WHERE user_id = $10`
 	_, err = tx.Exec(statement, submitter.Email, submitter.Name, submitter.CallName, submitter.PasswordHash, submitter.Institution,
-		submitter.Public, submitter.GDPRConsent, submitter.Active, submitter.Id)
+		submitter.Public, submitter.GDPRConsent, submitter.Active, submitter.EmailVerified, submitter.Id)
 	if err != nil {
 		tx.Rollback()
 		log.Println("Error updating user", submitter.Id, err.Error())
@@ -288,7 +336,7 @@ func getWantedRoles(tx *sql.Tx, roles []models.Role) ([]int, error) {
 
 func (m *SubmitterModel) List() ([]models.Submitter, error) {
 	var submitters []models.Submitter
-	statement := `SELECT u.user_id, u.email, u.name, u.call_name, u.institution, u.password_hash, u.is_public, u.gdpr_consent, u.active, array_agg(role_id) AS role_ids 
+	statement := `SELECT u.user_id, u.email, u.name, u.call_name, u.institution, u.password_hash, u.is_public, u.gdpr_consent, u.active, u.email_verified, array_agg(role_id) AS role_ids 
 FROM mibig_submitters.submitters AS u
 LEFT JOIN mibig_submitters.rel_submitters_roles USING (user_id)
 GROUP BY user_id`
@@ -307,7 +355,7 @@ GROUP BY user_id`
 		var role_ids []int64
 
 		err := rows.Scan(&submitter.Id, &submitter.Email, &submitter.Name, &submitter.CallName, &submitter.Institution,
-			&submitter.PasswordHash, &submitter.Public, &submitter.GDPRConsent, &submitter.Active, pq.Array(&role_ids))
+			&submitter.PasswordHash, &submitter.Public, &submitter.GDPRConsent, &submitter.Active, &submitter.EmailVerified, pq.Array(&role_ids))
 		if err != nil {
 			return nil, err
 		}
@@ -356,3 +404,54 @@ func (m *SubmitterModel) Delete(email string) error {
 	}
 	return nil
 }
+
+// anonymisedTables hold rows about a user that are removed when the account is anonymised
+var anonymisedTables = []string{
+	"mibig_submitters.rel_submitters_roles",
+	"mibig_submitters.sessions",
+	"mibig_submitters.apikeys",
+	"mibig_submitters.tokens",
+	"mibig_submitters.lockouts",
+	"mibig_submitters.recovery_codes",
+	"mibig_submitters.two_factor",
+}
+
+// Anonymise erases the personal data of a submitter while keeping the user id, so changelog
+// entries referencing it stay valid. Roles, credentials and login state are removed, so the
+// account can't be used to log in anymore.
+func (m *SubmitterModel) Anonymise(userId string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range anonymisedTables {
+		_, err = tx.Exec("DELETE FROM "+table+" WHERE user_id = $1", userId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	statement := `UPDATE mibig_submitters.submitters SET
+email = user_id || '@anonymised.invalid', name = '', call_name = '', institution = '', password_hash = '',
+is_public = FALSE, gdpr_consent = FALSE, active = FALSE
+WHERE user_id = $1`
+	result, err := tx.Exec(statement, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	return tx.Commit()
+}
@@ -62,10 +62,12 @@ func TestSubmitterModel(t *testing.T) {
 	t.Run("GetRolesByName", mt.GetRolesByName)
 	t.Run("Insert", mt.Insert)
 	t.Run("Get", mt.Get)
+	t.Run("GetById", mt.GetById)
 	t.Run("Authenticate", mt.Authenticate)
 	t.Run("ChangePassword", mt.ChangePassword)
 	t.Run("Update", mt.Update)
 	t.Run("List", mt.List)
+	t.Run("Anonymise", mt.Anonymise)
 	t.Run("Delete", mt.Delete)
 
 }
@@ -165,6 +167,22 @@ func (mt *SubmitterModelTest) Get(t *testing.T) {
 	}
 }
 
+func (mt *SubmitterModelTest) GetById(t *testing.T) {
+	submitter, err := mt.m.GetById("AAAAAAAAAAAAAAAAAAAAAAAB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if submitter.Email != "alice@example.org" {
+		t.Errorf("GetById unexpected result: %v", submitter)
+	}
+
+	_, err = mt.m.GetById("ZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if err != models.ErrNotFound {
+		t.Errorf("Unexpected error getting missing user. Expected %s, got %s", models.ErrNotFound, err)
+	}
+}
+
 func (mt *SubmitterModelTest) Authenticate(t *testing.T) {
 	eve, err := mt.m.Get("eve@example.org", false)
 	if err != nil {
@@ -271,6 +289,33 @@ func (mt *SubmitterModelTest) List(t *testing.T) {
 	t.Fail()
 }
 
+func (mt *SubmitterModelTest) Anonymise(t *testing.T) {
+	err := mt.m.Anonymise("AAAAAAAAAAAAAAAAAAAAAAAC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &models.Submitter{
+		Id:           "AAAAAAAAAAAAAAAAAAAAAAAC",
+		Email:        "AAAAAAAAAAAAAAAAAAAAAAAC@anonymised.invalid",
+		PasswordHash: []uint8{},
+	}
+
+	submitter, err := mt.m.GetById("AAAAAAAAAAAAAAAAAAAAAAAC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(expected, submitter) {
+		t.Errorf("Anonymise unexpected results:\n%s", cmp.Diff(expected, submitter))
+	}
+
+	err = mt.m.Anonymise("ZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if err != models.ErrNotFound {
+		t.Errorf("Unexpected error anonymising missing user. Expected %s, got %s", models.ErrNotFound, err)
+	}
+}
+
 func (mt *SubmitterModelTest) Delete(t *testing.T) {
 	eve, err := mt.m.Get("eve@example.org", false)
 	if err != nil {
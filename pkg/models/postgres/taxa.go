@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type TaxaModel struct {
+	DB *sql.DB
+}
+
+// ReferencedIds lists the tax ids referenced by MIBiG entries
+func (m *TaxaModel) ReferencedIds() ([]int, error) {
+	statement := `SELECT DISTINCT tax_id FROM mibig.entries WHERE tax_id IS NOT NULL ORDER BY tax_id`
+
+	rows, err := m.DB.Query(statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (m *TaxaModel) Get(ids []int) ([]models.Taxon, error) {
+	statement := `SELECT tax_id, ` + taxonColumns + `
+	FROM mibig.taxa
+	WHERE tax_id = ANY($1::int[])
+	ORDER BY tax_id`
+
+	rows, err := m.DB.Query(statement, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxa []models.Taxon
+	for rows.Next() {
+		var taxId int
+		var taxon nullTaxon
+
+		targets := append([]interface{}{&taxId}, taxon.targets()...)
+		if err = rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		taxa = append(taxa, *taxon.toTaxon(taxId))
+	}
+	return taxa, nil
+}
+
+func (m *TaxaModel) Upsert(taxa []models.Taxon) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	statement := `INSERT INTO mibig.taxa
+(tax_id, ` + taxonColumns + `)
+VALUES
+($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (tax_id) DO UPDATE SET
+superkingdom = EXCLUDED.superkingdom, kingdom = EXCLUDED.kingdom, phylum = EXCLUDED.phylum, class = EXCLUDED.class,
+taxonomic_order = EXCLUDED.taxonomic_order, family = EXCLUDED.family, genus = EXCLUDED.genus,
+species = EXCLUDED.species, name = EXCLUDED.name`
+
+	for _, t := range taxa {
+		_, err = tx.Exec(statement, t.TaxID, t.Superkingdom, t.Kingdom, t.Phylum, t.Class, t.Order, t.Family,
+			t.Genus, t.Species, t.Name)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/taxonomy"
+)
+
+// taxonColumns are the mibig.taxa columns read by nullTaxon, in scan order
+const taxonColumns = `superkingdom, kingdom, phylum, class, taxonomic_order, family, genus, species, name`
+
+type nullTaxon struct {
+	Superkingdom sql.NullString
+	Kingdom      sql.NullString
+	Phylum       sql.NullString
+	Class        sql.NullString
+	Order        sql.NullString
+	Family       sql.NullString
+	Genus        sql.NullString
+	Species      sql.NullString
+	Name         sql.NullString
+}
+
+func (n *nullTaxon) targets() []interface{} {
+	return []interface{}{&n.Superkingdom, &n.Kingdom, &n.Phylum, &n.Class, &n.Order, &n.Family, &n.Genus, &n.Species, &n.Name}
+}
+
+func (n *nullTaxon) toTaxon(taxId int) *models.Taxon {
+	return &models.Taxon{
+		TaxID:        taxId,
+		Superkingdom: n.Superkingdom.String,
+		Kingdom:      n.Kingdom.String,
+		Phylum:       n.Phylum.String,
+		Class:        n.Class.String,
+		Order:        n.Order.String,
+		Family:       n.Family.String,
+		Genus:        n.Genus.String,
+		Species:      n.Species.String,
+		Name:         n.Name.String,
+	}
+}
+
+// TaxonCounts returns the taxa of the given entries with the number of entries per taxon,
+// or of all entries if ids is nil
+func (m *MibigModel) TaxonCounts(ids []int) ([]models.TaxonCount, error) {
+	statement := `SELECT tax_id, ` + taxonColumns + `, COUNT(entry_id)
+	FROM mibig.entries
+	LEFT JOIN mibig.taxa USING (tax_id)
+	WHERE $1::int[] IS NULL OR entry_id = ANY($1::int[])
+	GROUP BY tax_id, ` + taxonColumns + `
+	ORDER BY tax_id`
+
+	var id_array interface{}
+	if ids != nil {
+		id_array = pq.Array(ids)
+	}
+
+	rows, err := m.DB.Query(statement, id_array)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.TaxonCount
+	for rows.Next() {
+		var taxId, count int
+		var taxon nullTaxon
+
+		targets := append([]interface{}{&taxId}, taxon.targets()...)
+		targets = append(targets, &count)
+		if err = rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		counts = append(counts, models.TaxonCount{Taxon: *taxon.toTaxon(taxId), Count: count})
+	}
+	return counts, nil
+}
+
+// rankExpressions maps the taxonomic ranks to the mibig.taxa expressions holding their names.
+// Species names are binomials, as the species column only holds the epithet.
+var rankExpressions = map[string]string{
+	"superkingdom": "superkingdom",
+	"kingdom":      "kingdom",
+	"phylum":       "phylum",
+	"class":        "class",
+	"order":        "taxonomic_order",
+	"family":       "family",
+	"genus":        "genus",
+	"species":      "CASE WHEN species IS NULL OR species = '' THEN NULL ELSE concat_ws(' ', genus, species) END",
+}
+
+// TaxonomyNode returns the taxon of the given rank and name with its lineage,
+// child taxa and the entries directly under it
+func (m *MibigModel) TaxonomyNode(rank string, name string) (*models.TaxonomyNode, error) {
+	expression, ok := rankExpressions[rank]
+	if !ok {
+		return nil, models.ErrInvalidRank
+	}
+
+	statement := `SELECT acc, tax_id, ` + taxonColumns + `
+	FROM mibig.entries
+	JOIN mibig.taxa USING (tax_id)
+	WHERE lower(` + expression + `) = lower($1)
+	ORDER BY acc`
+
+	rows, err := m.DB.Query(statement, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.Entry
+	for rows.Next() {
+		var entry models.Entry
+		var taxon nullTaxon
+
+		targets := append([]interface{}{&entry.Acc, &entry.TaxID}, taxon.targets()...)
+		if err = rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		entry.Taxon = taxon.toTaxon(entry.TaxID)
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return taxonomy.Browse(rank, name, entries), nil
+}
@@ -4,7 +4,7 @@ INSERT INTO mibig.taxa (tax_id, superkingdom, phylum, class, taxonomic_order, fa
 
 INSERT INTO mibig.entries (entry_id, acc, tax_id, data) VALUES
 (535, 'BGC0000535', '1360', '{"cluster": {"loci": {"evidence": ["Gene expression correlated with compound production", "Sequence-based prediction"], "accession": "HM219853.1", "completeness": "complete"}, "ripp": {"cyclic": true, "subclass": "Lanthipeptide", "peptidases": ["ADJ56357.1"], "precursor_genes": [{"gene_id": "ADJ56352.1", "crosslinks": [{"first_AA": 3, "second_AA": 7, "crosslink_type": "Thioether"}, {"first_AA": 13, "second_AA": 19, "crosslink_type": "Thioether"}, {"first_AA": 23, "second_AA": 26, "crosslink_type": "Thioether"}, {"first_AA": 8, "second_AA": 11, "crosslink_type": "Thioether"}, {"first_AA": 25, "second_AA": 28, "crosslink_type": "Thioether"}], "core_sequence": ["ITSISLCTPGCKTGALMGCNMKTATCHCSIHVSK"], "leader_sequence": "MSTKDFNLDLVSVSKKDSGASPR"}]}, "genes": {"annotations": [{"id": "nisB", "functions": [{"category": "Tailoring", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}], "tailoring": ["Dehydration"]}, {"id": "nisG", "functions": [{"category": "Resistance/immunity", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisE", "functions": [{"category": "Resistance/immunity", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisF", "functions": [{"category": "Resistance/immunity", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisK", "functions": [{"category": "Regulation", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisR", "functions": [{"category": "Regulation", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisI", "functions": [{"category": "Resistance/immunity", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisC", "functions": [{"category": "Tailoring", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}], "tailoring": ["Unknown"]}, {"id": "nisA", "functions": [{"category": "Precursor biosynthesis", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}, {"id": "nisT", "functions": [{"category": "Transport", "evidence": ["Knock-out", "Activity assay", "Sequence-based prediction"]}]}]}, "minimal": false, "compounds": [{"compound": "nisin A", "mol_mass": 3834.7822, "chem_acts": ["Antibacterial", "Signalling"], "chem_struct": "CCC(C)C1C(=O)NC(=C)C(=O)NC(C(=O)NC(CSCC(C(=O)N1)NC(=O)/C(=C/C)/NC(=O)C(C(C)CC)N)C(=O)NC2C(SCC(NC(=O)CNC(=O)C3CCCN3C2=O)C(=O)NC(CCCCN)C(=O)NC4C(SCC(NC(=O)CNC(=O)C(NC(=O)C(NC(=O)C(NC(=O)CNC4=O)C)CC(C)C)CCSC)C(=O)NC(CC(=O)N)C(=O)NC(CCSC)C(=O)NC(CCCCN)C(=O)NC5C(SCC6C(=O)NC(C(=O)NC(CSC(C(C(=O)N6)NC(=O)C(NC5=O)C)C)C(=O)NC(CO)C(=O)NC(C(C)CC)C(=O)NC(CC7=CNC=N7)C(=O)NC(C(C)C)C(=O)NC(=C)C(=O)NC(CCCCN)C(=O)O)CC8=CNC=N8)C)C)C)CC(C)C", "database_id": ["pubchem:16130280"], "chem_synonyms": ["nisin"], "molecular_formula": "C179N62O37S7"}], "ncbi_tax_id": "1360", "biosyn_class": ["RiPP"], "publications": ["pubmed:21183019"], "organism_name": "Lactococcus lactis subsp. lactis", "mibig_accession": "BGC0000535"}, "changelog": [{"version": "2.0", "comments": ["Migrated from v1.4"]}]}'),
-(1070, 'BGC0001070', '1214242', '{"cluster": {"nrp": {"cyclic": false, "nrps_genes": [{"gene_id": "CAN89633.1", "modules": [{"active": false, "a_substr_spec": {"evidence": ["Structure-based inference"], "epimerized": false, "proteinogenic": ["Glycine"]}, "c_dom_subtype": "LCL", "module_number": "6"}]}, {"gene_id": "CAN89638.1", "modules": [{"active": false, "a_substr_spec": {"evidence": ["Structure-based inference"], "epimerized": false, "aa_subcluster": ["CAN89641.1"], "nonproteinogenic": ["Beta-alanine"]}, "c_dom_subtype": "LCL", "module_number": "16"}]}]}, "loci": {"evidence": ["Knock-out studies"], "accession": "AM746336.1", "completeness": "complete"}, "genes": {"annotations": [{"id": "CAN89641.1", "functions": [{"category": "Unknown", "evidence": ["Activity assay"]}]}, {"id": "CAN89636.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Knock-out"]}]}, {"id": "CAN89639.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Activity assay"]}]}, {"id": "CAN89643.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Activity assay"]}]}, {"id": "CAN89641.1", "functions": [{"category": "Precursor biosynthesis", "evidence": ["Activity assay"]}]}]}, "minimal": false, "compounds": [{"compound": "kirromycin", "mol_mass": 790.568, "chem_acts": ["Antibacterial"], "chem_struct": "CC[C@H](C(=O)NC/C=C/C=C(\\\\C)/[C@H]([C@@H](C)[C@H]1[C@H]([C@H]([C@H](O1)/C=C/C=C/C=C(\\\\C)/C(=C\\\\2/C(=O)C=CNC2=O)/O)O)O)OC)[C@@]3([C@@H]([C@@H](C([C@@H](O3)/C=C/C=C\\\\C)(C)C)O)O)O", "database_id": ["pubchem:54697674", "chemspider:4883396"], "chem_targets": [{"target": "EF-Tu"}], "chem_synonyms": ["mocimycin", "delvomycin"], "molecular_formula": "C44H10N2O14"}], "polyketide": {"cyclic": false, "synthases": [{"genes": ["CAN89632.1", "CAN89633.1", "CAN89639.1", "CAN89643.1", "CAN89634.1", "CAN89635.1", "CAN89631.1", "CAN89636.1"], "modules": [{"genes": ["CAN89631.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "0", "at_specificities": ["Unknown"]}, {"genes": ["CAN89631.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "1", "at_specificities": ["Unknown"]}, {"genes": ["CAN89631.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "2", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "2", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "pks_mod_doms": ["Methylation"], "kr_stereochem": "D-OH", "module_number": "3", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "4", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "5", "at_specificities": ["Unknown"]}, {"genes": ["CAN89633.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "7", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "D-OH", "module_number": "7", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "pks_mod_doms": ["Methylation"], "kr_stereochem": "L-OH", "module_number": "8", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "9", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "D-OH", "module_number": "10", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "11", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "12", "at_specificities": ["Unknown"]}, {"genes": ["CAN89635.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "12", "at_specificities": ["Unknown"]}, {"genes": ["CAN89635.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "13", "at_specificities": ["Unknown"]}, {"genes": ["CAN89636.1"], "domains": ["Ketosynthase", "Acyltransferase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "14", "at_specificities": ["Methylmalonyl-CoA"]}, {"genes": ["CAN89636.1"], "domains": ["Ketosynthase", "Acyltransferase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "15", "at_specificities": ["Malonyl-CoA"]}], "subclass": ["Modular type I", "Trans-AT type I"], "trans_at": {"genes": ["CAN89639.1", "CAN89643.1"]}}], "subclasses": ["Other"], "starter_unit": "Acetyl-CoA"}, "ncbi_tax_id": "1214242", "biosyn_class": ["NRP", "Polyketide"], "publications": ["pubmed:4554808", "pubmed:4373734", "pubmed:18291322", "pubmed:21513880", "pubmed:19609288", "pubmed:21401713", "pubmed:23828654"], "organism_name": "Streptomyces collinus Tu 365", "mibig_accession": "BGC0001070"}, "changelog": [{"version": "2.0", "comments": ["Migrated from v1.4"]}]}');
+(1070, 'BGC0001070', '1214242', '{"cluster": {"nrp": {"cyclic": false, "nrps_genes": [{"gene_id": "CAN89633.1", "modules": [{"active": false, "a_substr_spec": {"evidence": ["Structure-based inference"], "epimerized": false, "proteinogenic": ["Glycine"]}, "c_dom_subtype": "LCL", "module_number": "6"}]}, {"gene_id": "CAN89638.1", "modules": [{"active": false, "a_substr_spec": {"evidence": ["Structure-based inference"], "epimerized": false, "aa_subcluster": ["CAN89641.1"], "nonproteinogenic": ["Beta-alanine"]}, "c_dom_subtype": "LCL", "module_number": "16"}]}]}, "loci": {"evidence": ["Knock-out studies"], "accession": "AM746336.1", "completeness": "complete"}, "genes": {"annotations": [{"id": "CAN89641.1", "functions": [{"category": "Unknown", "evidence": ["Activity assay"]}]}, {"id": "CAN89636.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Knock-out"]}]}, {"id": "CAN89639.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Activity assay"]}]}, {"id": "CAN89643.1", "functions": [{"category": "Scaffold biosynthesis", "evidence": ["Activity assay"]}]}, {"id": "CAN89641.1", "functions": [{"category": "Precursor biosynthesis", "evidence": ["Activity assay"]}]}]}, "minimal": false, "compounds": [{"compound": "kirromycin", "mol_mass": 790.568, "chem_acts": ["Antibacterial"], "chem_struct": "CC[C@H](C(=O)NC/C=C/C=C(\\\\C)/[C@H]([C@@H](C)[C@H]1[C@H]([C@H]([C@H](O1)/C=C/C=C/C=C(\\\\C)/C(=C\\\\2/C(=O)C=CNC2=O)/O)O)O)OC)[C@@]3([C@@H]([C@@H](C([C@@H](O3)/C=C/C=C\\\\C)(C)C)O)O)O", "database_id": ["pubchem:54697674", "chemspider:4883396"], "chem_targets": [{"target": "EF-Tu"}], "chem_synonyms": ["mocimycin", "delvomycin"], "molecular_formula": "C44H10N2O14"}], "polyketide": {"cyclic": false, "synthases": [{"genes": ["CAN89632.1", "CAN89633.1", "CAN89639.1", "CAN89643.1", "CAN89634.1", "CAN89635.1", "CAN89631.1", "CAN89636.1"], "modules": [{"genes": ["CAN89631.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "0", "at_specificities": ["Unknown"]}, {"genes": ["CAN89631.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "1", "at_specificities": ["Unknown"]}, {"genes": ["CAN89631.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "2", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "2", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "pks_mod_doms": ["Methylation"], "kr_stereochem": "D-OH", "module_number": "3", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "4", "at_specificities": ["Unknown"]}, {"genes": ["CAN89632.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "5", "at_specificities": ["Unknown"]}, {"genes": ["CAN89633.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "7", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "D-OH", "module_number": "7", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "pks_mod_doms": ["Methylation"], "kr_stereochem": "L-OH", "module_number": "8", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "9", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "D-OH", "module_number": "10", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase", "Ketoreductase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "11", "at_specificities": ["Unknown"]}, {"genes": ["CAN89634.1"], "domains": ["Ketosynthase"], "kr_stereochem": "Unknown", "module_number": "12", "at_specificities": ["Unknown"]}, {"genes": ["CAN89635.1"], "domains": ["Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "12", "at_specificities": ["Unknown"]}, {"genes": ["CAN89635.1"], "domains": ["Ketosynthase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "13", "at_specificities": ["Unknown"]}, {"genes": ["CAN89636.1"], "domains": ["Ketosynthase", "Acyltransferase", "Ketoreductase", "Dehydratase", "Thiolation (ACP/PCP)"], "kr_stereochem": "L-OH", "module_number": "14", "at_specificities": ["Methylmalonyl-CoA"]}, {"genes": ["CAN89636.1"], "domains": ["Ketosynthase", "Acyltransferase", "Thiolation (ACP/PCP)"], "kr_stereochem": "Unknown", "module_number": "15", "at_specificities": ["Malonyl-CoA"]}], "subclass": ["Modular type I", "Trans-AT type I"], "trans_at": {"genes": ["CAN89639.1", "CAN89643.1"]}}], "subclasses": ["Other"], "starter_unit": "Acetyl-CoA"}, "ncbi_tax_id": "1214242", "biosyn_class": ["NRP", "Polyketide"], "publications": ["pubmed:4554808", "pubmed:4373734", "pubmed:18291322", "pubmed:21513880", "pubmed:19609288", "pubmed:21401713", "pubmed:23828654"], "organism_name": "Streptomyces collinus Tu 365", "mibig_accession": "BGC0001070"}, "changelog": [{"version": "2.0", "comments": ["Migrated from v1.4"]}, {"version": "2.1", "comments": ["Added compound targets"], "contributors": ["AAAAAAAAAAAAAAAAAAAAAAAB"]}]}');
 
 INSERT INTO mibig.compounds (name, entry_id) VALUES
 ('nisin A', 535),
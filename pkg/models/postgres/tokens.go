@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -13,7 +15,14 @@ type TokenModel struct {
 	DB *sql.DB
 }
 
-func (t *TokenModel) Generate(email string) (*models.Token, error) {
+// hashToken returns the form tokens are stored in, so a leaked table can't be used to reset passwords
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Generate creates a token for the user with the given email
+func (t *TokenModel) Generate(email string, purpose string, lifetime time.Duration) (*models.Token, error) {
 	randomString, err := utils.GenerateUid(32)
 	if err != nil {
 		return nil, err
@@ -21,7 +30,8 @@ func (t *TokenModel) Generate(email string) (*models.Token, error) {
 
 	token := models.Token{
 		Token:   randomString,
-		Expires: time.Now().Add(time.Hour * 24),
+		Purpose: purpose,
+		Expires: time.Now().Add(lifetime),
 	}
 
 	tx, err := t.DB.Begin()
@@ -29,14 +39,18 @@ func (t *TokenModel) Generate(email string) (*models.Token, error) {
 		return nil, err
 	}
 
-	row := tx.QueryRow("SELECT user_id FROM users WHERE email = ?", email)
+	row := tx.QueryRow("SELECT user_id FROM mibig_submitters.submitters WHERE email = $1", email)
 	err = row.Scan(&token.UserId)
 	if err != nil {
 		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
 		return nil, err
 	}
 
-	_, err = tx.Exec("INSERT INTO tokens VALUES(?, ?, ?)", token.Token, token.UserId, token.Expires.Unix())
+	statement := `INSERT INTO mibig_submitters.tokens (token_hash, user_id, purpose, expires) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(statement, hashToken(token.Token), token.UserId, token.Purpose, token.Expires)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -50,34 +64,46 @@ func (t *TokenModel) Generate(email string) (*models.Token, error) {
 	return &token, nil
 }
 
-func (t *TokenModel) Validate(userId int, token string) (bool, error) {
-	var dbToken models.Token
-	var unixTime int64
-	row := t.DB.QueryRow("SELECT token_id, user_id, expires FROM tokens WHERE token_id = ? AND user_id = ?", token, userId)
-
-	err := row.Scan(&dbToken.Token, &dbToken.UserId, &unixTime)
+// Validate returns the user id a valid token for the given purpose was issued to
+func (t *TokenModel) Validate(token string, purpose string) (string, error) {
+	var userId string
+	statement := `SELECT user_id FROM mibig_submitters.tokens WHERE token_hash = $1 AND purpose = $2 AND expires > now()`
+	err := t.DB.QueryRow(statement, hashToken(token), purpose).Scan(&userId)
 	if err != nil {
-		if errors.Is(sql.ErrNoRows, err) {
-			return false, nil
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrInvalidToken
 		}
-		return false, err
+		return "", err
 	}
-	dbToken.Expires = time.Unix(unixTime, 0)
 
-	return time.Now().Before(dbToken.Expires), nil
+	return userId, nil
 }
 
+// Remove deletes a token, returning ErrInvalidToken if it was already used
 func (t *TokenModel) Remove(token string) error {
-	_, err := t.DB.Exec(`DELETE FROM tokens WHERE token_id = ?`, token)
+	result, err := t.DB.Exec(`DELETE FROM mibig_submitters.tokens WHERE token_hash = $1`, hashToken(token))
 	if err != nil {
 		return err
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrInvalidToken
+	}
+
 	return nil
 }
 
+func (t *TokenModel) RemoveAll(userId string) error {
+	_, err := t.DB.Exec(`DELETE FROM mibig_submitters.tokens WHERE user_id = $1`, userId)
+	return err
+}
+
 func (t *TokenModel) Expire() error {
-	_, err := t.DB.Exec(`DELETE FROM tokens WHERE expires < strftime('%s', 'now')`)
+	_, err := t.DB.Exec(`DELETE FROM mibig_submitters.tokens WHERE expires < now()`)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type TwoFactorModel struct {
+	DB *sql.DB
+}
+
+func (m *TwoFactorModel) Get(userId string) (*models.TwoFactor, error) {
+	twoFactor := models.TwoFactor{UserId: userId}
+	statement := `SELECT secret, confirmed, last_step FROM mibig_submitters.two_factor WHERE user_id = $1`
+	err := m.DB.QueryRow(statement, userId).Scan(&twoFactor.Secret, &twoFactor.Confirmed, &twoFactor.LastStep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+func (m *TwoFactorModel) Enrol(userId string, secret string) error {
+	statement := `INSERT INTO mibig_submitters.two_factor (user_id, secret) VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, confirmed = FALSE, last_step = 0`
+	_, err := m.DB.Exec(statement, userId, secret)
+	return err
+}
+
+func (m *TwoFactorModel) Confirm(userId string, recoveryCodes []string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`UPDATE mibig_submitters.two_factor SET confirmed = TRUE WHERE user_id = $1`, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	_, err = tx.Exec(`DELETE FROM mibig_submitters.recovery_codes WHERE user_id = $1`, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, code := range recoveryCodes {
+		_, err = tx.Exec(`INSERT INTO mibig_submitters.recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userId, hashToken(code))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *TwoFactorModel) UseStep(userId string, step int64) error {
+	statement := `UPDATE mibig_submitters.two_factor SET last_step = $1 WHERE user_id = $2 AND last_step < $1`
+	result, err := m.DB.Exec(statement, step, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrInvalidToken
+	}
+	return nil
+}
+
+func (m *TwoFactorModel) UseRecoveryCode(userId string, code string) error {
+	statement := `DELETE FROM mibig_submitters.recovery_codes WHERE user_id = $1 AND code_hash = $2`
+	result, err := m.DB.Exec(statement, userId, hashToken(code))
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrInvalidToken
+	}
+	return nil
+}
+
+func (m *TwoFactorModel) Disable(userId string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM mibig_submitters.recovery_codes WHERE user_id = $1`, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM mibig_submitters.two_factor WHERE user_id = $1`, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// TypeTree returns the biosynthetic class hierarchy with cumulative entry counts
+func (m *MibigModel) TypeTree() ([]*models.BgcType, error) {
+	rows, err := m.DB.Query(`SELECT bgc_type_id, parent_id, term, description, safe_class FROM mibig.bgc_types`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var types []models.BgcType
+	for rows.Next() {
+		var bgcType models.BgcType
+		var parentId sql.NullInt64
+		var description, class sql.NullString
+		if err = rows.Scan(&bgcType.Id, &parentId, &bgcType.Term, &description, &class); err != nil {
+			return nil, err
+		}
+		bgcType.ParentId = int(parentId.Int64)
+		bgcType.Description = description.String
+		bgcType.Class = class.String
+		types = append(types, bgcType)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	typeRows, err := m.DB.Query(`SELECT entry_id, bgc_type_id FROM mibig.rel_entries_types`)
+	if err != nil {
+		return nil, err
+	}
+	defer typeRows.Close()
+
+	entryTypes := make(map[int][]int)
+	for typeRows.Next() {
+		var entryId, typeId int
+		if err = typeRows.Scan(&entryId, &typeId); err != nil {
+			return nil, err
+		}
+		entryTypes[entryId] = append(entryTypes[entryId], typeId)
+	}
+
+	return models.BuildTypeTree(types, entryTypes), nil
+}
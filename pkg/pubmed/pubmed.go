@@ -0,0 +1,111 @@
+// Package pubmed parses PubMed XML as returned by the NCBI efetch utility.
+package pubmed
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+type articleSet struct {
+	Articles []article `xml:"PubmedArticle"`
+}
+
+type article struct {
+	PMID       string      `xml:"MedlineCitation>PMID"`
+	Title      innerText   `xml:"MedlineCitation>Article>ArticleTitle"`
+	Journal    journal     `xml:"MedlineCitation>Article>Journal"`
+	Pagination string      `xml:"MedlineCitation>Article>Pagination>MedlinePgn"`
+	Authors    []author    `xml:"MedlineCitation>Article>AuthorList>Author"`
+	ArticleIds []articleId `xml:"PubmedData>ArticleIdList>ArticleId"`
+}
+
+type journal struct {
+	Title           string `xml:"Title"`
+	ISOAbbreviation string `xml:"ISOAbbreviation"`
+	Volume          string `xml:"JournalIssue>Volume"`
+	Issue           string `xml:"JournalIssue>Issue"`
+	Year            string `xml:"JournalIssue>PubDate>Year"`
+	MedlineDate     string `xml:"JournalIssue>PubDate>MedlineDate"`
+}
+
+type author struct {
+	LastName       string `xml:"LastName"`
+	ForeName       string `xml:"ForeName"`
+	Initials       string `xml:"Initials"`
+	CollectiveName string `xml:"CollectiveName"`
+}
+
+type articleId struct {
+	IdType string `xml:"IdType,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// innerText keeps the text of elements with inline markup like <i>
+type innerText struct {
+	Raw string `xml:",innerxml"`
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+func (t innerText) String() string {
+	return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(t.Raw, "")))
+}
+
+// Parse reads a PubmedArticleSet document and returns the contained publications
+func Parse(r io.Reader) ([]models.Publication, error) {
+	var set articleSet
+	if err := xml.NewDecoder(r).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	publications := make([]models.Publication, 0, len(set.Articles))
+	for _, a := range set.Articles {
+		publication := models.Publication{
+			Id:      "pubmed:" + strings.TrimSpace(a.PMID),
+			Title:   strings.TrimSuffix(a.Title.String(), "."),
+			Journal: a.Journal.ISOAbbreviation,
+			Volume:  a.Journal.Volume,
+			Issue:   a.Journal.Issue,
+			Pages:   a.Pagination,
+		}
+		if publication.Journal == "" {
+			publication.Journal = a.Journal.Title
+		}
+
+		year := a.Journal.Year
+		if year == "" {
+			year = yearPattern.FindString(a.Journal.MedlineDate)
+		}
+		publication.Year, _ = strconv.Atoi(year)
+
+		for _, au := range a.Authors {
+			switch {
+			case au.CollectiveName != "":
+				publication.Authors = append(publication.Authors, au.CollectiveName)
+			case au.ForeName != "":
+				publication.Authors = append(publication.Authors, au.LastName+", "+au.ForeName)
+			case au.Initials != "":
+				publication.Authors = append(publication.Authors, au.LastName+", "+au.Initials)
+			default:
+				publication.Authors = append(publication.Authors, au.LastName)
+			}
+		}
+
+		for _, id := range a.ArticleIds {
+			if id.IdType == "doi" {
+				publication.Doi = strings.TrimSpace(id.Value)
+			}
+		}
+
+		publications = append(publications, publication)
+	}
+
+	return publications, nil
+}
@@ -0,0 +1,48 @@
+package pubmed
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestParse(t *testing.T) {
+	handle, err := os.Open("./testdata/articles.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handle.Close()
+
+	publications, err := Parse(handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.Publication{
+		{
+			Id:      "pubmed:21183019",
+			Title:   "Insights into the biosynthesis of nisin A",
+			Authors: []string{"User, Alice", "User, B", "MIBiG Consortium"},
+			Journal: "Chem Biol",
+			Year:    2011,
+			Volume:  "18",
+			Issue:   "1",
+			Pages:   "90-100",
+			Doi:     "10.1016/j.chembiol.2010.11.010",
+		},
+		{
+			Id:      "pubmed:4554808",
+			Title:   "Kirromycin, a new antibiotic",
+			Journal: "The Journal of antibiotics",
+			Year:    1972,
+			Volume:  "25",
+		},
+	}
+
+	if !cmp.Equal(expected, publications) {
+		t.Errorf("Unexpected publications:\n%s", cmp.Diff(expected, publications))
+	}
+}
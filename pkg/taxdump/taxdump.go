@@ -0,0 +1,224 @@
+// Package taxdump reads the NCBI taxonomy dump files (names.dmp, nodes.dmp, merged.dmp
+// and delnodes.dmp) and resolves tax ids into MIBiG taxon lineages.
+package taxdump
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+var (
+	ErrUnknownTaxId = errors.New("taxdump: unknown tax id")
+	ErrDeleted      = errors.New("taxdump: tax id was deleted")
+	ErrLoop         = errors.New("taxdump: loop in lineage")
+)
+
+type node struct {
+	Parent int
+	Rank   string
+}
+
+// Taxdump holds the parts of the NCBI taxonomy needed to resolve lineages
+type Taxdump struct {
+	names   map[int]string
+	nodes   map[int]node
+	merged  map[int]int
+	deleted map[int]bool
+}
+
+func New() *Taxdump {
+	return &Taxdump{
+		names:   make(map[int]string),
+		nodes:   make(map[int]node),
+		merged:  make(map[int]int),
+		deleted: make(map[int]bool),
+	}
+}
+
+// ReadNames reads the scientific names from names.dmp
+func (t *Taxdump) ReadNames(r io.Reader) error {
+	return readDump(r, 4, func(fields []string) error {
+		if fields[3] != "scientific name" {
+			return nil
+		}
+		taxId, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		t.names[taxId] = fields[1]
+		return nil
+	})
+}
+
+// ReadNodes reads the parent and rank of each node from nodes.dmp
+func (t *Taxdump) ReadNodes(r io.Reader) error {
+	return readDump(r, 3, func(fields []string) error {
+		taxId, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		parent, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return err
+		}
+		t.nodes[taxId] = node{Parent: parent, Rank: fields[2]}
+		return nil
+	})
+}
+
+// ReadMerged reads the old to new tax id mappings from merged.dmp
+func (t *Taxdump) ReadMerged(r io.Reader) error {
+	return readDump(r, 2, func(fields []string) error {
+		oldId, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		newId, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return err
+		}
+		t.merged[oldId] = newId
+		return nil
+	})
+}
+
+// ReadDelnodes reads the deleted tax ids from delnodes.dmp
+func (t *Taxdump) ReadDelnodes(r io.Reader) error {
+	return readDump(r, 1, func(fields []string) error {
+		taxId, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		t.deleted[taxId] = true
+		return nil
+	})
+}
+
+// readDump splits the "\t|\t" separated lines of a dump file and hands them to handle
+func readDump(r io.Reader, minFields int, handle func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSuffix(strings.TrimRight(scanner.Text(), "\r"), "\t|")
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t|\t")
+		if len(fields) < minFields {
+			return fmt.Errorf("taxdump: line %d: expected at least %d fields, got %d", lineNo, minFields, len(fields))
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if err := handle(fields); err != nil {
+			return fmt.Errorf("taxdump: line %d: %s", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// MergedInto returns the tax id a merged tax id was merged into
+func (t *Taxdump) MergedInto(taxId int) (int, bool) {
+	newId, ok := t.merged[taxId]
+	return newId, ok
+}
+
+// Taxon resolves the lineage of a tax id, following merges.
+// The returned taxon keeps the requested tax id, so existing references stay valid.
+func (t *Taxdump) Taxon(taxId int) (*models.Taxon, error) {
+	current := taxId
+	if newId, ok := t.merged[taxId]; ok {
+		current = newId
+	}
+	if t.deleted[current] {
+		return nil, ErrDeleted
+	}
+	if _, ok := t.nodes[current]; !ok {
+		return nil, ErrUnknownTaxId
+	}
+
+	taxon := &models.Taxon{TaxID: taxId, Name: t.names[current]}
+	seen := make(map[int]bool)
+	for id := current; ; {
+		if seen[id] {
+			return nil, ErrLoop
+		}
+		seen[id] = true
+
+		n, ok := t.nodes[id]
+		if !ok {
+			return nil, ErrUnknownTaxId
+		}
+		setRank(taxon, n.Rank, t.names[id])
+
+		if n.Parent == id {
+			break
+		}
+		id = n.Parent
+	}
+
+	// MIBiG stores the species epithet, the genus is kept separately
+	if taxon.Genus != "" && strings.HasPrefix(taxon.Species, taxon.Genus+" ") {
+		taxon.Species = strings.TrimPrefix(taxon.Species, taxon.Genus+" ")
+	}
+
+	return taxon, nil
+}
+
+func setRank(taxon *models.Taxon, rank, name string) {
+	switch rank {
+	case "superkingdom", "domain":
+		taxon.Superkingdom = name
+	case "kingdom":
+		taxon.Kingdom = name
+	case "phylum":
+		taxon.Phylum = name
+	case "class":
+		taxon.Class = name
+	case "order":
+		taxon.Order = name
+	case "family":
+		taxon.Family = name
+	case "genus":
+		taxon.Genus = name
+	case "species":
+		taxon.Species = name
+	}
+}
+
+// Change is a single differing column between two versions of a taxon
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff lists the columns that differ between the stored and the resolved taxon
+func Diff(old, new *models.Taxon) []Change {
+	var changes []Change
+	for _, rank := range models.TaxonomicRanks {
+		if o, n := rankField(old, rank), rankField(new, rank); o != n {
+			changes = append(changes, Change{Field: rank, Old: o, New: n})
+		}
+	}
+	if old.Name != new.Name {
+		changes = append(changes, Change{Field: "name", Old: old.Name, New: new.Name})
+	}
+	return changes
+}
+
+// rankField returns the stored value of a rank column, unlike Taxon.RankValue that builds binomials
+func rankField(taxon *models.Taxon, rank string) string {
+	if rank == "species" {
+		return taxon.Species
+	}
+	return taxon.RankValue(rank)
+}
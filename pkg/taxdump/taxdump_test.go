@@ -0,0 +1,95 @@
+package taxdump
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func loadTestdata(t *testing.T) *Taxdump {
+	t.Helper()
+	dump := New()
+	files := []struct {
+		Name string
+		Read func(f *os.File) error
+	}{
+		{"names.dmp", func(f *os.File) error { return dump.ReadNames(f) }},
+		{"nodes.dmp", func(f *os.File) error { return dump.ReadNodes(f) }},
+		{"merged.dmp", func(f *os.File) error { return dump.ReadMerged(f) }},
+		{"delnodes.dmp", func(f *os.File) error { return dump.ReadDelnodes(f) }},
+	}
+	for _, file := range files {
+		f, err := os.Open(filepath.Join("testdata", file.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = file.Read(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dump
+}
+
+func TestTaxon(t *testing.T) {
+	dump := loadTestdata(t)
+
+	lineage := models.Taxon{Superkingdom: "Bacteria", Phylum: "Actinomycetota", Class: "Actinomycetes",
+		Order: "Kitasatosporales", Family: "Streptomycetaceae", Genus: "Streptomyces"}
+
+	strain := lineage
+	strain.TaxID, strain.Species, strain.Name = 100226, "coelicolor", "Streptomyces coelicolor A3(2)"
+	merged := lineage
+	merged.TaxID, merged.Species, merged.Name = 1901, "coelicolor", "Streptomyces coelicolor"
+	unclassified := lineage
+	unclassified.TaxID, unclassified.Species, unclassified.Name = 1931, "sp.", "Streptomyces sp."
+
+	tests := []struct {
+		Name     string
+		TaxId    int
+		Expected *models.Taxon
+		Err      error
+	}{
+		{"strain", 100226, &strain, nil},
+		{"merged", 1901, &merged, nil},
+		{"unclassified", 1931, &unclassified, nil},
+		{"deleted", 12345, nil, ErrDeleted},
+		{"unknown", 42, nil, ErrUnknownTaxId},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			taxon, err := dump.Taxon(tt.TaxId)
+			if err != tt.Err {
+				t.Fatalf("Expected error %v, got %v", tt.Err, err)
+			}
+			if !cmp.Equal(tt.Expected, taxon) {
+				t.Errorf("Unexpected taxon:\n%s", cmp.Diff(tt.Expected, taxon))
+			}
+		})
+	}
+
+	if newId, ok := dump.MergedInto(1901); !ok || newId != 1902 {
+		t.Errorf("Expected 1901 to be merged into 1902, got %d, %v", newId, ok)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &models.Taxon{TaxID: 1901, Superkingdom: "Bacteria", Phylum: "Actinobacteria", Genus: "Streptomyces", Species: "coelicolor", Name: "Streptomyces coelicolor"}
+	new := &models.Taxon{TaxID: 1901, Superkingdom: "Bacteria", Phylum: "Actinomycetota", Genus: "Streptomyces", Species: "coelicolor", Name: "Streptomyces coelicolor"}
+
+	expected := []Change{{Field: "phylum", Old: "Actinobacteria", New: "Actinomycetota"}}
+	changes := Diff(old, new)
+	if !cmp.Equal(expected, changes) {
+		t.Errorf("Unexpected changes:\n%s", cmp.Diff(expected, changes))
+	}
+
+	if changes := Diff(new, new); changes != nil {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
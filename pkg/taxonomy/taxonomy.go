@@ -0,0 +1,155 @@
+// Package taxonomy builds taxonomic trees from the MIBiG taxa.
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// Node is a taxon in the tree, with the number of entries in its subtree
+type Node struct {
+	Name     string  `json:"name"`
+	Rank     string  `json:"rank"`
+	Count    int     `json:"count"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// BuildTree arranges the taxa into a superkingdom to species hierarchy.
+// Ranks without a value are skipped, attaching the lower ranks to the closest ancestor.
+func BuildTree(taxa []models.TaxonCount) *Node {
+	root := &Node{Name: "root", Rank: "root"}
+
+	for _, taxon := range taxa {
+		root.Count += taxon.Count
+		current := root
+		for _, rank := range models.TaxonomicRanks {
+			name := taxon.RankValue(rank)
+			if name == "" {
+				continue
+			}
+			current = current.child(name, rank)
+			current.Count += taxon.Count
+		}
+	}
+
+	root.sort()
+	return root
+}
+
+func (n *Node) child(name, rank string) *Node {
+	for _, child := range n.Children {
+		if child.Name == name && child.Rank == rank {
+			return child
+		}
+	}
+	child := &Node{Name: name, Rank: rank}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+func (n *Node) sort() {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	for _, child := range n.Children {
+		child.sort()
+	}
+}
+
+// Newick renders the tree in Newick format, leaving the root unlabeled
+func (n *Node) Newick() string {
+	var b strings.Builder
+	n.writeNewick(&b, true)
+	b.WriteString(";")
+	return b.String()
+}
+
+func (n *Node) writeNewick(b *strings.Builder, isRoot bool) {
+	if len(n.Children) > 0 {
+		b.WriteString("(")
+		for i, child := range n.Children {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			child.writeNewick(b, false)
+		}
+		b.WriteString(")")
+	}
+	if !isRoot {
+		b.WriteString(newickLabel(n.Name))
+	}
+}
+
+func newickLabel(name string) string {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return "'" + strings.Replace(name, "'", "''", -1) + "'"
+		}
+	}
+	return name
+}
+
+// Browse describes the taxon of the given rank and name, based on the entries below it.
+// Entries without a value at the next lower rank are listed as directly under the taxon.
+func Browse(rank, name string, entries []models.Entry) *models.TaxonomyNode {
+	node := &models.TaxonomyNode{
+		Rank:     rank,
+		Name:     name,
+		Count:    len(entries),
+		Lineage:  []models.TaxonLevel{},
+		Children: []models.TaxonLevel{},
+		Entries:  []string{},
+	}
+
+	rankIndex := -1
+	for i, r := range models.TaxonomicRanks {
+		if r == rank {
+			rankIndex = i
+		}
+	}
+	if rankIndex < 0 || len(entries) == 0 {
+		return node
+	}
+
+	first := entries[0].Taxon
+	if first != nil {
+		node.Name = first.RankValue(rank)
+		for _, r := range models.TaxonomicRanks[:rankIndex] {
+			if value := first.RankValue(r); value != "" {
+				node.Lineage = append(node.Lineage, models.TaxonLevel{Rank: r, Name: value})
+			}
+		}
+	}
+
+	childIndex := make(map[models.TaxonLevel]int)
+	for _, entry := range entries {
+		child := nextLevel(entry.Taxon, rankIndex)
+		if child == nil {
+			node.Entries = append(node.Entries, entry.Acc)
+			continue
+		}
+		if i, ok := childIndex[*child]; ok {
+			node.Children[i].Count++
+			continue
+		}
+		childIndex[*child] = len(node.Children)
+		child.Count = 1
+		node.Children = append(node.Children, *child)
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool { return node.Children[i].Name < node.Children[j].Name })
+	sort.Strings(node.Entries)
+	return node
+}
+
+func nextLevel(taxon *models.Taxon, rankIndex int) *models.TaxonLevel {
+	if taxon == nil {
+		return nil
+	}
+	for _, r := range models.TaxonomicRanks[rankIndex+1:] {
+		if value := taxon.RankValue(r); value != "" {
+			return &models.TaxonLevel{Rank: r, Name: value}
+		}
+	}
+	return nil
+}
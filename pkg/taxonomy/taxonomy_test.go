@@ -0,0 +1,98 @@
+package taxonomy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+var testTaxa = []models.TaxonCount{
+	{Taxon: models.Taxon{TaxID: 1360, Superkingdom: "Bacteria", Phylum: "Firmicutes", Class: "Bacilli", Order: "Lactobacillales",
+		Family: "Streptococcaceae", Genus: "Lactococcus", Species: "lactis", Name: "Lactococcus lactis subsp. lactis"}, Count: 1},
+	{Taxon: models.Taxon{TaxID: 1214242, Superkingdom: "Bacteria", Phylum: "Actinobacteria", Class: "Actinobacteria", Order: "Streptomycetales",
+		Family: "Streptomycetaceae", Genus: "Streptomyces", Species: "collinus", Name: "Streptomyces collinus Tu 365"}, Count: 2},
+	{Taxon: models.Taxon{TaxID: 1931, Superkingdom: "Bacteria", Phylum: "Actinobacteria", Class: "Actinobacteria", Order: "Streptomycetales",
+		Family: "Streptomycetaceae", Genus: "Streptomyces", Name: "Streptomyces sp."}, Count: 3},
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree(testTaxa)
+
+	expected := &Node{Name: "root", Rank: "root", Count: 6, Children: []*Node{
+		{Name: "Bacteria", Rank: "superkingdom", Count: 6, Children: []*Node{
+			{Name: "Actinobacteria", Rank: "phylum", Count: 5, Children: []*Node{
+				{Name: "Actinobacteria", Rank: "class", Count: 5, Children: []*Node{
+					{Name: "Streptomycetales", Rank: "order", Count: 5, Children: []*Node{
+						{Name: "Streptomycetaceae", Rank: "family", Count: 5, Children: []*Node{
+							{Name: "Streptomyces", Rank: "genus", Count: 5, Children: []*Node{
+								{Name: "Streptomyces collinus", Rank: "species", Count: 2},
+							}},
+						}},
+					}},
+				}},
+			}},
+			{Name: "Firmicutes", Rank: "phylum", Count: 1, Children: []*Node{
+				{Name: "Bacilli", Rank: "class", Count: 1, Children: []*Node{
+					{Name: "Lactobacillales", Rank: "order", Count: 1, Children: []*Node{
+						{Name: "Streptococcaceae", Rank: "family", Count: 1, Children: []*Node{
+							{Name: "Lactococcus", Rank: "genus", Count: 1, Children: []*Node{
+								{Name: "Lactococcus lactis", Rank: "species", Count: 1},
+							}},
+						}},
+					}},
+				}},
+			}},
+		}},
+	}}
+
+	if !cmp.Equal(expected, tree) {
+		t.Errorf("Unexpected tree:\n%s", cmp.Diff(expected, tree))
+	}
+}
+
+func TestNewick(t *testing.T) {
+	tree := BuildTree(testTaxa)
+
+	expected := "((((((('Streptomyces collinus')Streptomyces)Streptomycetaceae)Streptomycetales)Actinobacteria)Actinobacteria," +
+		"((((('Lactococcus lactis')Lactococcus)Streptococcaceae)Lactobacillales)Bacilli)Firmicutes)Bacteria);"
+
+	if newick := tree.Newick(); newick != expected {
+		t.Errorf("Unexpected Newick string:\nwant %s\ngot  %s", expected, newick)
+	}
+
+	quoted := &Node{Name: "root", Children: []*Node{{Name: "O'Brien's bug"}}}
+	if newick := quoted.Newick(); newick != "('O''Brien''s bug');" {
+		t.Errorf("Unexpected quoting: %s", newick)
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	var entries []models.Entry
+	for i, taxon := range testTaxa {
+		taxon := taxon.Taxon
+		entries = append(entries, models.Entry{Acc: fmt.Sprintf("BGC%07d", i+1), TaxID: taxon.TaxID, Taxon: &taxon})
+	}
+
+	expected := &models.TaxonomyNode{
+		Rank:  "genus",
+		Name:  "Streptomyces",
+		Count: 2,
+		Lineage: []models.TaxonLevel{
+			{Rank: "superkingdom", Name: "Bacteria"},
+			{Rank: "phylum", Name: "Actinobacteria"},
+			{Rank: "class", Name: "Actinobacteria"},
+			{Rank: "order", Name: "Streptomycetales"},
+			{Rank: "family", Name: "Streptomycetaceae"},
+		},
+		Children: []models.TaxonLevel{{Rank: "species", Name: "Streptomyces collinus", Count: 1}},
+		Entries:  []string{"BGC0000003"},
+	}
+
+	node := Browse("genus", "streptomyces", entries[1:])
+	if !cmp.Equal(expected, node) {
+		t.Errorf("Unexpected node:\n%s", cmp.Diff(expected, node))
+	}
+}
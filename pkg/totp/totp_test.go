@@ -0,0 +1,96 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B, SHA1 variant
+func TestHotpVectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		Time     int64
+		Expected string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		if code := hotp(key, uint64(Step(time.Unix(tt.Time, 0))), 8); code != tt.Expected {
+			t.Errorf("At %d: expected %s, got %s", tt.Time, tt.Expected, code)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "050471" {
+		t.Errorf("Expected %s, got %s", "050471", code)
+	}
+
+	tests := []struct {
+		Name     string
+		Time     time.Time
+		Expected bool
+	}{
+		{"same period", now, true},
+		{"previous period", now.Add(-Period), true},
+		{"next period", now.Add(Period), true},
+		{"too late", now.Add(3 * Period), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			step, ok := Verify(secret, code, tt.Time)
+			if ok != tt.Expected {
+				t.Fatalf("Expected %t, got %t", tt.Expected, ok)
+			}
+			if ok && step != Step(now) {
+				t.Errorf("Expected step %d, got %d", Step(now), step)
+			}
+		})
+	}
+
+	if _, ok := Verify(secret, "123", now); ok {
+		t.Errorf("Expected short code to fail")
+	}
+	if _, ok := Verify("not base32!", code, now); ok {
+		t.Errorf("Expected invalid secret to fail")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("Expected 32 characters, got %d", len(secret))
+	}
+	if _, err = Code(secret, time.Now()); err != nil {
+		t.Errorf("Expected generated secret to be usable, got %s", err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "MIBiG", "alice@example.com")
+	expected := "otpauth://totp/MIBiG:alice@example.com?algorithm=SHA1&digits=6&issuer=MIBiG&period=30&secret=JBSWY3DPEHPK3PXP"
+	if uri != expected {
+		t.Errorf("Expected %s, got %s", expected, uri)
+	}
+	if !strings.HasPrefix(ProvisioningURI("S", "My Service", "bob"), "otpauth://totp/My%20Service:bob?") {
+		t.Errorf("Expected label to be escaped")
+	}
+}
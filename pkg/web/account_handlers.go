@@ -0,0 +1,356 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// currentUser loads the active submitter the request's token was issued to
+func (app *application) currentUser(c *gin.Context) (*models.Submitter, bool) {
+	claims := c.MustGet("claims").(*Claims)
+	user, err := app.SubmitterModel.GetById(claims.Subject)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return nil, false
+		}
+		app.serverError(c, err)
+		return nil, false
+	}
+	if !user.Active {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return nil, false
+	}
+	return user, true
+}
+
+type accountProfile struct {
+	Id          string `json:"id"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	CallName    string `json:"call_name"`
+	Institution string `json:"institution"`
+	Public      bool   `json:"public"`
+	GDPRConsent bool   `json:"gdpr_consent"`
+	Active      bool   `json:"active"`
+	// EmailVerified is false for accounts still waiting for their verification link to be used
+	EmailVerified bool `json:"email_verified"`
+}
+
+func newAccountProfile(user *models.Submitter) accountProfile {
+	return accountProfile{
+		Id:          user.Id,
+		Email:       user.Email,
+		Name:        user.Name,
+		CallName:    user.CallName,
+		Institution: user.Institution,
+		Public:      user.Public,
+		GDPRConsent: user.GDPRConsent,
+		Active:      user.Active,
+
+		EmailVerified: user.EmailVerified,
+	}
+}
+
+type accountSession struct {
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+type accountDataExport struct {
+	Profile       accountProfile        `json:"profile"`
+	Roles         []string              `json:"roles"`
+	Contributions []models.Contribution `json:"contributions"`
+	Sessions      []accountSession      `json:"sessions"`
+	ApiKeys       []*apiKeyResult       `json:"api_keys"`
+	TwoFactor     bool                  `json:"two_factor"`
+	FailedLogins  int                   `json:"failed_logins"`
+	LockedUntil   *time.Time            `json:"locked_until,omitempty"`
+	// AuditLog holds the entries the user acted in or was the target of
+	AuditLog []models.AuditEntry `json:"audit_log"`
+}
+
+// accountData returns everything stored about the current user, as required for GDPR data access requests.
+// Secrets are left out, as is anything that is only stored as a hash.
+func (app *application) accountData(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	data, err := app.collectAccountData(user)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="mibig-account-data.json"`)
+	c.JSON(http.StatusOK, data)
+}
+
+func (app *application) collectAccountData(user *models.Submitter) (*accountDataExport, error) {
+	data := accountDataExport{
+		Profile: newAccountProfile(user),
+		Roles:   models.RolesToStrings(user.Roles),
+	}
+
+	var err error
+	if data.Contributions, err = app.MibigModel.Contributions(user.Id); err != nil {
+		return nil, err
+	}
+
+	sessions, err := app.SessionModel.List(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		data.Sessions = append(data.Sessions, accountSession{Created: session.Created, Expires: session.Expires})
+	}
+
+	keys, err := app.ApiKeyModel.List(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		data.ApiKeys = append(data.ApiKeys, newApiKeyResult(&keys[i]))
+	}
+
+	if data.TwoFactor, err = app.twoFactorEnabled(user.Id); err != nil {
+		return nil, err
+	}
+
+	lockout, err := app.LockoutModel.Get(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	data.FailedLogins = lockout.Failures
+	if !lockout.LockedUntil.IsZero() {
+		data.LockedUntil = &lockout.LockedUntil
+	}
+
+	if data.AuditLog, err = app.accountAuditLog(user.Id); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// accountAuditLog returns the audit entries a user acted in or was the target of, newest first
+func (app *application) accountAuditLog(userId string) ([]models.AuditEntry, error) {
+	acted, err := app.AuditModel.List(models.AuditFilter{Actor: userId})
+	if err != nil {
+		return nil, err
+	}
+	targeted, err := app.AuditModel.List(models.AuditFilter{Target: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := acted
+	for _, entry := range targeted {
+		if entry.Actor != userId {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Id > entries[j].Id })
+	return entries, nil
+}
+
+// withdrawConsent revokes the GDPR consent, which also hides the user from contributor listings
+func (app *application) withdrawConsent(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	before := user.Audit()
+	user.GDPRConsent = false
+	user.Public = false
+	if err := app.SubmitterModel.Update(user, ""); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(user.Id, models.AuditUserUpdate, user.Id, before, user.Audit())
+
+	c.Status(http.StatusNoContent)
+}
+
+type passwordConfirmation struct {
+	Password string `json:"password"`
+}
+
+// eraseAccount anonymises the current user after confirming their password.
+// Changelogs keep referring to the user id, but no personal data is left.
+func (app *application) eraseAccount(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req passwordConfirmation
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if _, ok := app.confirmPassword(c, user.Email, req.Password, http.StatusForbidden); !ok {
+		return
+	}
+
+	if err := app.SubmitterModel.Anonymise(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err := app.SessionModel.RevokeAll(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err := app.TwoFactorModel.Disable(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err := app.ApiKeyModel.RevokeAll(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err := app.TokenModel.RemoveAll(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err := app.LockoutModel.Reset(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.logger.Infow("account erased", "user", user.Id)
+	app.audit(user.Id, models.AuditUserAnonymise, user.Id, nil, nil)
+	c.Status(http.StatusNoContent)
+}
+
+type accountResult struct {
+	accountProfile
+	Roles []string `json:"roles"`
+}
+
+func newAccountResult(user *models.Submitter) *accountResult {
+	return &accountResult{accountProfile: newAccountProfile(user), Roles: models.RolesToStrings(user.Roles)}
+}
+
+func (app *application) account(c *gin.Context) {
+	user, ok := app.currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newAccountResult(user))
+}
+
+// accountUpdate holds the profile fields users may change themselves, omitted fields are kept
+type accountUpdate struct {
+	Name        *string `json:"name"`
+	CallName    *string `json:"call_name"`
+	Institution *string `json:"institution"`
+	Public      *bool   `json:"public"`
+}
+
+func (app *application) updateAccount(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req accountUpdate
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	before := user.Audit()
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			c.JSON(http.StatusBadRequest, queryError{Message: "name must not be empty", Error: true})
+			return
+		}
+		user.Name = *req.Name
+	}
+	if req.CallName != nil {
+		user.CallName = *req.CallName
+	}
+	if req.Institution != nil {
+		user.Institution = *req.Institution
+	}
+	if req.Public != nil {
+		// Without GDPR consent, the profile can't be shown publicly
+		if *req.Public && !user.GDPRConsent {
+			c.JSON(http.StatusBadRequest, queryError{Message: "a public profile requires GDPR consent", Error: true})
+			return
+		}
+		user.Public = *req.Public
+	}
+
+	// Roles, email and activation state are loaded from the database and stay untouched
+	if err := app.SubmitterModel.Update(user, ""); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(user.Id, models.AuditUserUpdate, user.Id, before, user.Audit())
+	c.JSON(http.StatusOK, newAccountResult(user))
+}
+
+const minPasswordLength = 8
+
+type passwordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (app *application) changePassword(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req passwordChange
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if len(req.NewPassword) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, queryError{Message: "new password is too short", Error: true})
+		return
+	}
+
+	if _, ok := app.confirmPassword(c, user.Email, req.CurrentPassword, http.StatusForbidden); !ok {
+		return
+	}
+
+	if err := app.SubmitterModel.ChangePassword(user.Id, req.NewPassword); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	// Log out everywhere, in case the old password was compromised
+	if err := app.SessionModel.RevokeAll(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(user.Id, models.AuditUserPassword, user.Id, nil, nil)
+
+	c.Status(http.StatusNoContent)
+}
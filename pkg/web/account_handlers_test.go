@@ -0,0 +1,307 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+// authenticatedRequest sends a request with the given bearer token and returns status and body
+func authenticatedRequest(t *testing.T, client *http.Client, method, url, token string, body io.Reader) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, data
+}
+
+func TestAccountData(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+
+	status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account/data", "", nil)
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected %d without token, got %d", http.StatusUnauthorized, status)
+	}
+
+	key, _, err := app.ApiKeyModel.Create("AAAAAAAAAAAAAAAAAAAAAAAA", "scripts", []string{"submitter"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.LockoutModel.RecordFailure("AAAAAAAAAAAAAAAAAAAAAAAA", 5, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	app.audit("AAAAAAAAAAAAAAAAAAAAAAAB", models.AuditUserRoles, "AAAAAAAAAAAAAAAAAAAAAAAA", nil, nil)
+	app.audit("AAAAAAAAAAAAAAAAAAAAAAAB", models.AuditUserRoles, "AAAAAAAAAAAAAAAAAAAAAAAB", nil, nil)
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account/data", newTestToken(t, "submitter"), nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+
+	var data accountDataExport
+	if err := json.Unmarshal(body, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.Sessions) != 1 {
+		t.Errorf("Expected the session of the mock model, got %v", data.Sessions)
+	}
+	if len(data.AuditLog) != 1 || data.AuditLog[0].Target != "AAAAAAAAAAAAAAAAAAAAAAAA" {
+		t.Errorf("Expected the audit entry targeting the user, got %v", data.AuditLog)
+	}
+	data.Sessions = nil
+	data.AuditLog = nil
+
+	expected := accountDataExport{
+		Profile: accountProfile{Id: "AAAAAAAAAAAAAAAAAAAAAAAA", Email: "alice@example.com", Name: "Alice User", CallName: "Alice",
+			Institution: "Testing", Public: true, GDPRConsent: true, Active: true},
+		Roles:         []string{"submitter"},
+		Contributions: []models.Contribution{{Accession: "BGC0000001", Versions: []string{"2.0"}}},
+		ApiKeys:       []*apiKeyResult{{Id: key.Id, Name: "scripts", Scopes: []string{"submitter"}, Created: key.Created}},
+		FailedLogins:  1,
+	}
+	if !cmp.Equal(expected, data) {
+		t.Errorf("Unexpected account data:\n%s", cmp.Diff(expected, data))
+	}
+	if strings.Contains(string(body), "password") {
+		t.Errorf("Account data must not contain the password hash: %s", body)
+	}
+}
+
+func TestWithdrawConsent(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/withdraw-consent", newTestToken(t, "submitter"), nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("Expected %d, got %d", http.StatusNoContent, status)
+	}
+
+	user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.GDPRConsent || user.Public {
+		t.Errorf("Expected consent and public profile to be withdrawn, got %v", user)
+	}
+	if !user.Active || len(user.Roles) != 1 {
+		t.Errorf("Expected account to stay active with its roles, got %v", user)
+	}
+}
+
+func TestEraseAccount(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	if _, _, err := app.ApiKeyModel.Create("AAAAAAAAAAAAAAAAAAAAAAAA", "scripts", []string{"submitter"}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.LockoutModel.RecordFailure("AAAAAAAAAAAAAAAAAAAAAAAA", 5, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Status int
+	}{
+		{"wrong password", `{"password": "wrong"}`, http.StatusForbidden},
+		{"erase", `{"password": "secret"}`, http.StatusNoContent},
+		{"already erased", `{"password": "secret"}`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/erase", token, strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+		})
+	}
+
+	user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "" || user.Email == "alice@example.com" || user.Active {
+		t.Errorf("Expected user to be anonymised, got %v", user)
+	}
+	keys, err := app.ApiKeyModel.List(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected API keys to be revoked, got %v", keys)
+	}
+	lockout, err := app.LockoutModel.Get(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockout.Failures != 0 {
+		t.Errorf("Expected failed logins to be cleared, got %d", lockout.Failures)
+	}
+}
+
+func TestPasswordConfirmationLockout(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	// Endpoints confirming the password count failures towards the account lockout like logins do
+	for i := 0; i < maxLoginFailures(); i++ {
+		status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/erase", token, strings.NewReader(`{"password": "wrong"}`))
+		if status != http.StatusForbidden {
+			t.Fatalf("Expected %d, got %d", http.StatusForbidden, status)
+		}
+	}
+
+	tests := []struct {
+		Name string
+		Path string
+		Body string
+	}{
+		{"erase", "/api/v1/account/erase", `{"password": "secret"}`},
+		{"password", "/api/v1/account/password", `{"current_password": "secret", "new_password": "long enough"}`},
+		{"2fa disable", "/api/v1/account/2fa/disable", `{"password": "secret"}`},
+		{"login", "/api/v1/login", `{"email": "alice@example.com", "password": "secret"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+tt.Path, token, strings.NewReader(tt.Body))
+			if status != http.StatusTooManyRequests {
+				t.Errorf("Expected locked account to get %d, got %d", http.StatusTooManyRequests, status)
+			}
+		})
+	}
+
+	user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.Active {
+		t.Errorf("Expected account not to be erased while locked")
+	}
+}
+
+func TestAccount(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account", newTestToken(t, "submitter"), nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+
+	var result accountResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Email != "alice@example.com" || !cmp.Equal([]string{"submitter"}, result.Roles) {
+		t.Errorf("Unexpected account: %v", result)
+	}
+}
+
+func TestUpdateAccount(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	tests := []struct {
+		Name     string
+		Body     string
+		Status   int
+		Expected accountProfile
+	}{
+		{"partial update", `{"call_name": "Ali", "institution": "Elsewhere"}`, http.StatusOK, accountProfile{
+			Id: "AAAAAAAAAAAAAAAAAAAAAAAA", Email: "alice@example.com", Name: "Alice User", CallName: "Ali",
+			Institution: "Elsewhere", Public: true, GDPRConsent: true, Active: true}},
+		{"roles are ignored", `{"name": "Alice Q. User", "public": false, "roles": ["admin"]}`, http.StatusOK, accountProfile{
+			Id: "AAAAAAAAAAAAAAAAAAAAAAAA", Email: "alice@example.com", Name: "Alice Q. User", CallName: "Ali",
+			Institution: "Elsewhere", Public: false, GDPRConsent: true, Active: true}},
+		{"empty name", `{"name": ""}`, http.StatusBadRequest, accountProfile{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "PUT", ts.URL+"/api/v1/account", token, strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, status)
+			}
+			if tt.Status != http.StatusOK {
+				return
+			}
+
+			user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile := newAccountProfile(user); !cmp.Equal(tt.Expected, profile) {
+				t.Errorf("Unexpected profile:\n%s", cmp.Diff(tt.Expected, profile))
+			}
+			if roles := models.RolesToStrings(user.Roles); !cmp.Equal([]string{"submitter"}, roles) {
+				t.Errorf("Expected roles to be unchanged, got %v", roles)
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Status int
+	}{
+		{"wrong current password", `{"current_password": "wrong", "new_password": "much more secret"}`, http.StatusForbidden},
+		{"too short", `{"current_password": "secret", "new_password": "short"}`, http.StatusBadRequest},
+		{"change", `{"current_password": "secret", "new_password": "much more secret"}`, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/password", token, strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+		})
+	}
+
+	if _, err := app.SubmitterModel.Authenticate("alice@example.com", "much more secret"); err != nil {
+		t.Errorf("Expected new password to work, got %s", err)
+	}
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account", token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected sessions to be revoked after password change, got %d", status)
+	}
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+func (app *application) invalidateCache(c *gin.Context) {
+	if app.MibigCache != nil {
+		app.MibigCache.Invalidate()
+		app.logger.Infow("cache invalidated", "user", actor(c))
+		app.audit(actor(c), models.AuditCacheInvalidate, "cache", nil, nil)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (app *application) listUsers(c *gin.Context) {
+	users, err := app.SubmitterModel.List()
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	results := make([]*accountResult, 0, len(users))
+	for i := range users {
+		results = append(results, newAccountResult(&users[i]))
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// userFromParam loads the user given by the :id parameter
+func (app *application) userFromParam(c *gin.Context) (*models.Submitter, bool) {
+	user, err := app.SubmitterModel.GetById(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return nil, false
+		}
+		app.serverError(c, err)
+		return nil, false
+	}
+	return user, true
+}
+
+func (app *application) getUser(c *gin.Context) {
+	user, ok := app.userFromParam(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newAccountResult(user))
+}
+
+type roleAssignment struct {
+	Roles []string `json:"roles"`
+}
+
+// setUserRoles replaces a user's roles, like "user edit add-role" and "user edit remove-role" combined
+func (app *application) setUserRoles(c *gin.Context) {
+	user, ok := app.userFromParam(c)
+	if !ok {
+		return
+	}
+
+	var req roleAssignment
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	names := utils.UnionString(nil, req.Roles)
+	roles, err := app.SubmitterModel.GetRolesByName(names)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusBadRequest, queryError{Message: "unknown role", Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	// Don't let admins lock themselves out
+	if user.Id == actor(c) && !models.HasRole(roles, "admin") {
+		c.JSON(http.StatusBadRequest, queryError{Message: "you can't remove your own admin role", Error: true})
+		return
+	}
+
+	before := user.Audit()
+	user.Roles = roles
+	if err = app.SubmitterModel.Update(user, ""); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.logger.Infow("roles changed", "user", user.Id, "roles", names, "by", actor(c))
+	app.audit(actor(c), models.AuditUserRoles, user.Id, before, user.Audit())
+	c.JSON(http.StatusOK, newAccountResult(user))
+}
+
+func (app *application) activateUser(c *gin.Context) {
+	app.setUserActive(c, true)
+}
+
+// deactivateUser disables a user's account and ends all their sessions
+func (app *application) deactivateUser(c *gin.Context) {
+	app.setUserActive(c, false)
+}
+
+func (app *application) setUserActive(c *gin.Context, active bool) {
+	user, ok := app.userFromParam(c)
+	if !ok {
+		return
+	}
+
+	if !active && user.Id == actor(c) {
+		c.JSON(http.StatusBadRequest, queryError{Message: "you can't deactivate your own account", Error: true})
+		return
+	}
+
+	before := user.Audit()
+	user.Active = active
+	if err := app.SubmitterModel.Update(user, ""); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if !active {
+		if err := app.SessionModel.RevokeAll(user.Id); err != nil {
+			app.serverError(c, err)
+			return
+		}
+		// Pending verification links would otherwise activate the account again
+		if err := app.TokenModel.RemoveAll(user.Id); err != nil {
+			app.serverError(c, err)
+			return
+		}
+	}
+
+	app.logger.Infow("account activation changed", "user", user.Id, "active", active, "by", actor(c))
+	action := models.AuditUserDeactivate
+	if active {
+		action = models.AuditUserActivate
+	}
+	app.audit(actor(c), action, user.Id, before, user.Audit())
+	c.JSON(http.StatusOK, newAccountResult(user))
+}
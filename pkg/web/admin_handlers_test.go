@@ -0,0 +1,158 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestInvalidateCache(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name   string
+		Token  string
+		Status int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"not an admin", newTestToken(t, "submitter"), http.StatusUnauthorized},
+		{"submitter claiming admin", newTestToken(t, "admin"), http.StatusUnauthorized},
+		{"admin", newAdminToken(t), http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			before := app.MibigCache.LastModified()
+			time.Sleep(time.Millisecond)
+
+			req, err := http.NewRequest("POST", ts.URL+"/api/v1/admin/cache/invalidate", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.Token)
+			}
+
+			response, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close()
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+
+			invalidated := app.MibigCache.LastModified().After(before)
+			if invalidated != (tt.Status == http.StatusNoContent) {
+				t.Errorf("Unexpected cache invalidation state: %v", invalidated)
+			}
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/users", newTestToken(t, "submitter"), nil)
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected %d for submitter, got %d", http.StatusUnauthorized, status)
+	}
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/users", newAdminToken(t), nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var users []accountResult
+	if err := json.Unmarshal(body, &users); err != nil {
+		t.Fatal(err)
+	}
+	var emails []string
+	for _, user := range users {
+		emails = append(emails, user.Email)
+	}
+	if expected := []string{"alice@example.com", "admin@example.com"}; !cmp.Equal(expected, emails) {
+		t.Errorf("Unexpected users: %s", cmp.Diff(expected, emails))
+	}
+}
+
+func TestSetUserRoles(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newAdminToken(t)
+
+	tests := []struct {
+		Name   string
+		User   string
+		Body   string
+		Status int
+	}{
+		{"unknown user", "ZZZZZZZZZZZZZZZZZZZZZZZZ", `{"roles": ["curator"]}`, http.StatusNotFound},
+		{"unknown role", "AAAAAAAAAAAAAAAAAAAAAAAA", `{"roles": ["overlord"]}`, http.StatusBadRequest},
+		{"own admin role", "AAAAAAAAAAAAAAAAAAAAAAAB", `{"roles": ["curator"]}`, http.StatusBadRequest},
+		{"promote", "AAAAAAAAAAAAAAAAAAAAAAAA", `{"roles": ["curator", "submitter"]}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "PUT", ts.URL+"/api/v1/admin/users/"+tt.User+"/roles", token, strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+		})
+	}
+
+	user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"curator", "submitter"}; !cmp.Equal(expected, models.RolesToStrings(user.Roles)) {
+		t.Errorf("Unexpected roles: %s", cmp.Diff(expected, models.RolesToStrings(user.Roles)))
+	}
+
+	// Demotion applies to tokens issued before it
+	aliceToken := newTestToken(t, "submitter")
+	if status, _ := authenticatedRequest(t, ts.Client(), "PUT", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/roles", token, strings.NewReader(`{"roles": ["guest"]}`)); status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	if status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/submit", aliceToken, strings.NewReader(`{}`)); status != http.StatusUnauthorized {
+		t.Errorf("Expected guest to be unable to submit, got %d", status)
+	}
+}
+
+func TestSetUserActive(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newAdminToken(t)
+	aliceToken := newTestToken(t)
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAB/deactivate", token, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected %d deactivating own account, got %d", http.StatusBadRequest, status)
+	}
+
+	status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/deactivate", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	if !strings.Contains(string(body), `"active":false`) {
+		t.Errorf("Expected inactive account, got %s", body)
+	}
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account", aliceToken, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected sessions of deactivated user to be revoked, got %d", status)
+	}
+
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/activate", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	login(t, ts.URL+"/api/v1/login", ts.Client(), `{"email": "alice@example.com", "password": "secret"}`)
+}
@@ -0,0 +1,146 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+const defaultApiKeyLifetimeDays = 365
+
+type apiKeyResult struct {
+	Id       string     `json:"id"`
+	Name     string     `json:"name"`
+	Scopes   []string   `json:"scopes"`
+	Created  time.Time  `json:"created"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+	// Key is only returned when the key is created
+	Key string `json:"key,omitempty"`
+}
+
+func newApiKeyResult(key *models.ApiKey) *apiKeyResult {
+	result := apiKeyResult{
+		Id:      key.Id,
+		Name:    key.Name,
+		Scopes:  key.Scopes,
+		Created: key.Created,
+	}
+	if !key.Expires.IsZero() {
+		result.Expires = &key.Expires
+	}
+	if !key.LastUsed.IsZero() {
+		result.LastUsed = &key.LastUsed
+	}
+	return &result
+}
+
+// sessionUser is currentUser for endpoints that must not be reachable with an API key,
+// so a leaked key can't be used to take over the account or read its personal data
+func (app *application) sessionUser(c *gin.Context) (*models.Submitter, bool) {
+	if _, viaApiKey := c.Get("apikey"); viaApiKey {
+		c.JSON(http.StatusForbidden, queryError{Message: "this endpoint requires a login session", Error: true})
+		return nil, false
+	}
+	return app.currentUser(c)
+}
+
+func (app *application) listApiKeys(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	keys, err := app.ApiKeyModel.List(user.Id)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	results := make([]*apiKeyResult, 0, len(keys))
+	for i := range keys {
+		results = append(results, newApiKeyResult(&keys[i]))
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+type apiKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays *int     `json:"expires_in_days"`
+}
+
+// createApiKey creates a key limited to the given scopes, which have to be roles the user holds.
+// Without scopes, the key is limited to the user's current roles.
+func (app *application) createApiKey(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req apiKeyRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, queryError{Message: "name is required", Error: true})
+		return
+	}
+
+	userRoles := models.RolesToStrings(user.Roles)
+	scopes := utils.UnionString(nil, req.Scopes)
+	if len(req.Scopes) == 0 {
+		scopes = userRoles
+	}
+	if len(utils.IntersectString(scopes, userRoles)) != len(scopes) {
+		c.JSON(http.StatusBadRequest, queryError{Message: "scopes must be roles you hold", Error: true})
+		return
+	}
+
+	days := defaultApiKeyLifetimeDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+	if days <= 0 {
+		c.JSON(http.StatusBadRequest, queryError{Message: "expires_in_days must be positive", Error: true})
+		return
+	}
+
+	key, secret, err := app.ApiKeyModel.Create(user.Id, req.Name, scopes, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(user.Id, models.AuditApiKeyCreate, user.Id, nil, gin.H{"id": key.Id, "scopes": key.Scopes, "expires": key.Expires})
+
+	result := newApiKeyResult(key)
+	result.Key = secret
+	c.JSON(http.StatusCreated, result)
+}
+
+func (app *application) revokeApiKey(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	if err := app.ApiKeyModel.Revoke(user.Id, c.Param("id")); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(user.Id, models.AuditApiKeyRevoke, user.Id, gin.H{"id": c.Param("id")}, nil)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func apiKeyAuthenticatedRequest(t *testing.T, client *http.Client, method, url, key string, body io.Reader) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "ApiKey "+key)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	return response.StatusCode
+}
+
+func TestCreateApiKey(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Status int
+		Scopes []string
+	}{
+		{"no name", `{}`, http.StatusBadRequest, nil},
+		{"scope not held", `{"name": "cron", "scopes": ["admin"]}`, http.StatusBadRequest, nil},
+		{"negative expiry", `{"name": "cron", "expires_in_days": -1}`, http.StatusBadRequest, nil},
+		{"default scopes", `{"name": "cron"}`, http.StatusCreated, []string{"submitter"}},
+		{"explicit scopes", `{"name": "pipeline", "scopes": ["submitter"], "expires_in_days": 7}`, http.StatusCreated, []string{"submitter"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/apikeys", token, strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, status)
+			}
+			if status != http.StatusCreated {
+				return
+			}
+			var result apiKeyResult
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Key == "" || result.Expires == nil {
+				t.Errorf("Expected key and expiry, got %s", body)
+			}
+			if !cmp.Equal(tt.Scopes, result.Scopes) {
+				t.Errorf("Unexpected scopes: %s", cmp.Diff(tt.Scopes, result.Scopes))
+			}
+		})
+	}
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account/apikeys", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var keys []apiKeyResult
+	if err := json.Unmarshal(body, &keys); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(keys))
+	}
+	for _, key := range keys {
+		if key.Key != "" {
+			t.Errorf("Expected listed keys not to include the secret")
+		}
+	}
+}
+
+func TestApiKeyAuthentication(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/apikeys", token, strings.NewReader(`{"name": "cron"}`))
+	if status != http.StatusCreated {
+		t.Fatalf("Expected %d, got %d", http.StatusCreated, status)
+	}
+	var created apiKeyResult
+	if err := json.Unmarshal(body, &created); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := apiKeyAuthenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", created.Key, nil); status != http.StatusOK {
+		t.Errorf("Expected API key to work, got %d", status)
+	}
+	if status := apiKeyAuthenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", created.Id+".wrong", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected wrong secret to fail, got %d", status)
+	}
+	if status := apiKeyAuthenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/apikeys", created.Key, strings.NewReader(`{"name": "more"}`)); status != http.StatusForbidden {
+		t.Errorf("Expected API keys not to manage API keys, got %d", status)
+	}
+
+	// A leaked key must not be enough to take over or erase the account
+	sessionOnly := []struct {
+		Method string
+		Path   string
+		Body   string
+	}{
+		{"POST", "/api/v1/account/erase", `{"password": "secret"}`},
+		{"POST", "/api/v1/account/password", `{"current_password": "secret", "new_password": "long enough"}`},
+		{"PUT", "/api/v1/account", `{"name": "Mallory"}`},
+		{"GET", "/api/v1/account/data", ""},
+		{"POST", "/api/v1/account/withdraw-consent", ""},
+	}
+	for _, tt := range sessionOnly {
+		var body io.Reader
+		if tt.Body != "" {
+			body = strings.NewReader(tt.Body)
+		}
+		if status := apiKeyAuthenticatedRequest(t, ts.Client(), tt.Method, ts.URL+tt.Path, created.Key, body); status != http.StatusForbidden {
+			t.Errorf("Expected %s %s to require a login session, got %d", tt.Method, tt.Path, status)
+		}
+	}
+	if _, err := app.SubmitterModel.Authenticate("alice@example.com", "secret"); err != nil {
+		t.Errorf("Expected account to be unchanged, got %s", err)
+	}
+
+	keys, err := app.ApiKeyModel.List("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].LastUsed.IsZero() {
+		t.Errorf("Expected last use to be recorded, got %v", keys)
+	}
+
+	// Scopes are limited to the roles the user still holds
+	if status, _ := authenticatedRequest(t, ts.Client(), "PUT", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/roles", newAdminToken(t), strings.NewReader(`{"roles": ["guest"]}`)); status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	if status := apiKeyAuthenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/submit", created.Key, strings.NewReader(`{}`)); status != http.StatusUnauthorized {
+		t.Errorf("Expected key of demoted user to lose its scope, got %d", status)
+	}
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "DELETE", ts.URL+"/api/v1/account/apikeys/"+created.Id, token, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected %d, got %d", http.StatusNoContent, status)
+	}
+	if status, _ := authenticatedRequest(t, ts.Client(), "DELETE", ts.URL+"/api/v1/account/apikeys/"+created.Id, token, nil); status != http.StatusNotFound {
+		t.Errorf("Expected %d revoking twice, got %d", http.StatusNotFound, status)
+	}
+	if status := apiKeyAuthenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", created.Key, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to fail, got %d", status)
+	}
+}
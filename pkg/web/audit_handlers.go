@@ -0,0 +1,76 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// audit records a privileged action in the audit log. As the action already happened,
+// failures are logged instead of failing the request.
+func (app *application) audit(actor, action, target string, before, after interface{}) {
+	entry, err := models.NewAuditEntry(actor, action, target, before, after)
+	if err == nil {
+		err = app.AuditModel.Record(entry)
+	}
+	if err != nil {
+		app.logger.Errorw("failed to record audit entry", "actor", actor, "action", action, "target", target, "error", err)
+	}
+}
+
+// actor returns the id of the user making an authenticated request
+func actor(c *gin.Context) string {
+	return c.MustGet("claims").(*Claims).Subject
+}
+
+// listAudit queries the audit log, filtered by the actor, action, target and since parameters.
+// since takes RFC 3339 timestamps or dates.
+func (app *application) listAudit(c *gin.Context) {
+	filter := models.AuditFilter{
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+		Target: c.Query("target"),
+		Limit:  defaultAuditLimit,
+	}
+
+	if since := c.Query("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			parsed, err = time.Parse("2006-01-02", since)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: "invalid since parameter", Error: true})
+			return
+		}
+		filter.Since = parsed
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 || parsed > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, queryError{Message: "limit must be between 1 and " + strconv.Itoa(maxAuditLimit), Error: true})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	entries, err := app.AuditModel.List(filter)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestAuditLog(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newAdminToken(t)
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/audit", newTestToken(t), nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected %d for non-admin, got %d", http.StatusUnauthorized, status)
+	}
+
+	status, _ := authenticatedRequest(t, ts.Client(), "PUT", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/roles", token, strings.NewReader(`{"roles": ["curator"]}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/admin/users/AAAAAAAAAAAAAAAAAAAAAAAA/deactivate", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+
+	tests := []struct {
+		Name    string
+		Query   string
+		Status  int
+		Actions []string
+	}{
+		{"all", "", http.StatusOK, []string{models.AuditUserDeactivate, models.AuditUserRoles}},
+		{"by action", "?action=user.roles", http.StatusOK, []string{models.AuditUserRoles}},
+		{"by target", "?target=AAAAAAAAAAAAAAAAAAAAAAAB", http.StatusOK, []string{}},
+		{"limit", "?limit=1", http.StatusOK, []string{models.AuditUserDeactivate}},
+		{"since", "?since=2000-01-01", http.StatusOK, []string{models.AuditUserDeactivate, models.AuditUserRoles}},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest, nil},
+		{"invalid limit", "?limit=0", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/audit"+tt.Query, token, nil)
+			if status != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, status)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			var entries []models.AuditEntry
+			if err := json.Unmarshal(body, &entries); err != nil {
+				t.Fatal(err)
+			}
+			actions := []string{}
+			for _, entry := range entries {
+				if entry.Actor != "AAAAAAAAAAAAAAAAAAAAAAAB" {
+					t.Errorf("Unexpected actor %s", entry.Actor)
+				}
+				actions = append(actions, entry.Action)
+			}
+			if strings.Join(actions, ",") != strings.Join(tt.Actions, ",") {
+				t.Errorf("Expected actions %v, got %v", tt.Actions, actions)
+			}
+		})
+	}
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/audit?action=user.roles", token, nil)
+	var entries []models.AuditEntry
+	if err := json.Unmarshal(body, &entries); err != nil || status != http.StatusOK {
+		t.Fatalf("Failed to list audit log: %d %v", status, err)
+	}
+	if !strings.Contains(string(entries[0].Before), `"submitter"`) || !strings.Contains(string(entries[0].After), `"curator"`) {
+		t.Errorf("Expected role change, got %s -> %s", entries[0].Before, entries[0].After)
+	}
+	for _, personal := range []string{"password", "alice@example.com", "Alice User", "Testing"} {
+		if strings.Contains(string(entries[0].Before)+string(entries[0].After), personal) {
+			t.Errorf("Audit log contains %q: %s -> %s", personal, entries[0].Before, entries[0].After)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const (
+	defaultCompoundPagination = 50
+	maxCompoundPagination     = 500
+)
+
+type compoundListResult struct {
+	Total     int                      `json:"total"`
+	Offset    int                      `json:"offset"`
+	Paginate  int                      `json:"paginate"`
+	Compounds []models.CompoundSummary `json:"compounds"`
+}
+
+func (app *application) compound(c *gin.Context) {
+	details, err := app.MibigModel.Compound(c.Param("name"))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+func (app *application) compounds(c *gin.Context) {
+	req := struct {
+		Offset   int `form:"offset"`
+		Paginate int `form:"paginate"`
+	}{Paginate: defaultCompoundPagination}
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+	if req.Offset < 0 {
+		c.JSON(http.StatusBadRequest, queryError{Message: "offset must not be negative", Error: true})
+		return
+	}
+	if req.Paginate <= 0 || req.Paginate > maxCompoundPagination {
+		c.JSON(http.StatusBadRequest, queryError{Message: "paginate must be between 1 and " + strconv.Itoa(maxCompoundPagination), Error: true})
+		return
+	}
+
+	compounds, total, err := app.MibigModel.Compounds(req.Offset, req.Paginate)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &compoundListResult{
+		Total:     total,
+		Offset:    req.Offset,
+		Paginate:  req.Paginate,
+		Compounds: compounds,
+	})
+}
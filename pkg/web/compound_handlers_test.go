@@ -0,0 +1,117 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestCompound(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name       string
+		Url        string
+		Status     int
+		Accessions []string
+	}{
+		{"by name", "/api/v1/compound/testomycin%20A", http.StatusOK, []string{"BGC0000001"}},
+		{"by synonym", "/api/v1/compound/Testomycin", http.StatusOK, []string{"BGC0000001"}},
+		{"unknown", "/api/v1/compound/nisin", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Get(ts.URL + tt.Url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+			if tt.Status != http.StatusOK {
+				return
+			}
+
+			var details models.CompoundDetails
+			if err := json.Unmarshal(body, &details); err != nil {
+				t.Fatal(err)
+			}
+
+			var accessions []string
+			for _, occurrence := range details.Entries {
+				accessions = append(accessions, occurrence.Accession)
+				if occurrence.Structure != "CC(=O)O" || occurrence.Formula != "C2H4O2" {
+					t.Errorf("Unexpected compound details: %v", occurrence.Compound)
+				}
+			}
+			if !cmp.Equal(tt.Accessions, accessions) {
+				t.Errorf("Unexpected accessions:\n%s", cmp.Diff(tt.Accessions, accessions))
+			}
+		})
+	}
+}
+
+func TestCompounds(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name     string
+		Url      string
+		Status   int
+		Expected *compoundListResult
+	}{
+		{"default", "/api/v1/compounds", http.StatusOK, &compoundListResult{Total: 3, Offset: 0, Paginate: 50,
+			Compounds: []models.CompoundSummary{{Name: "nisin A", Count: 1}, {Name: "testomycin A", Count: 1}, {Name: "testomycin B", Count: 2}}}},
+		{"paginated", "/api/v1/compounds?offset=1&paginate=1", http.StatusOK, &compoundListResult{Total: 3, Offset: 1, Paginate: 1,
+			Compounds: []models.CompoundSummary{{Name: "testomycin A", Count: 1}}}},
+		{"negative offset", "/api/v1/compounds?offset=-1", http.StatusBadRequest, nil},
+		{"zero paginate", "/api/v1/compounds?paginate=0", http.StatusBadRequest, nil},
+		{"negative paginate", "/api/v1/compounds?paginate=-1", http.StatusBadRequest, nil},
+		{"paginate too large", "/api/v1/compounds?paginate=501", http.StatusBadRequest, nil},
+		{"maximum paginate", "/api/v1/compounds?paginate=500", http.StatusOK, &compoundListResult{Total: 3, Offset: 0, Paginate: 500,
+			Compounds: []models.CompoundSummary{{Name: "nisin A", Count: 1}, {Name: "testomycin A", Count: 1}, {Name: "testomycin B", Count: 2}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Get(ts.URL + tt.Url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+			if tt.Expected == nil {
+				return
+			}
+
+			var result compoundListResult
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.Expected, &result) {
+				t.Errorf("Unexpected result:\n%s", cmp.Diff(tt.Expected, &result))
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/export"
+	"secondarymetabolites.org/mibig-api/pkg/queries"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+func (app *application) exportRdf(c *gin.Context) {
+	format := c.DefaultQuery("format", "turtle")
+	contentType, ok := export.RDFContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, queryError{Message: "Invalid format, use 'turtle' or 'ntriples'", Error: true})
+		return
+	}
+
+	entries, err := app.MibigModel.Entries(nil)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	graph, err := export.EntriesToGraph(entries)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	var out bytes.Buffer
+	if err = export.WriteRDF(&out, graph, format); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, out.Bytes())
+}
+
+type compoundExportRequest struct {
+	Query        *queries.Query `json:"query"`
+	SearchString string         `json:"search_string"`
+	Format       string         `json:"format"`
+}
+
+func (app *application) exportCompounds(c *gin.Context) {
+	var req compoundExportRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = "smiles"
+	}
+	contentType, ok := export.CompoundContentTypes[req.Format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, queryError{Message: "Invalid format, use 'smiles' or 'sdf'", Error: true})
+		return
+	}
+
+	var err error
+	if req.Query == nil && req.SearchString != "" {
+		req.Query, err = queries.NewQueryFromString(req.SearchString)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+	}
+
+	// Without a query, export the compounds of all entries
+	var entry_ids []int
+	if req.Query != nil {
+		entry_ids, err = app.MibigModel.Search(req.Query.Terms)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		if entry_ids == nil {
+			entry_ids = []int{}
+		}
+	}
+
+	entries, err := app.MibigModel.Entries(entry_ids)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	records, err := export.CompoundRecords(entries)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	var out bytes.Buffer
+	if err = export.WriteCompounds(&out, records, req.Format); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, out.Bytes())
+}
+
+type referenceExportRequest struct {
+	Accessions   []string       `json:"accessions"`
+	Query        *queries.Query `json:"query"`
+	SearchString string         `json:"search_string"`
+	Format       string         `json:"format"`
+}
+
+func (app *application) exportReferences(c *gin.Context) {
+	var req referenceExportRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = "bibtex"
+	}
+	contentType, ok := export.ReferenceContentTypes[req.Format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, queryError{Message: "Invalid format, use 'bibtex' or 'ris'", Error: true})
+		return
+	}
+
+	var err error
+	if req.Query == nil && req.SearchString != "" {
+		req.Query, err = queries.NewQueryFromString(req.SearchString)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+	}
+
+	if req.Query == nil && len(req.Accessions) == 0 {
+		c.JSON(http.StatusBadRequest, queryError{Message: "Need a list of accessions or a query", Error: true})
+		return
+	}
+
+	entry_ids := []int{}
+	if len(req.Accessions) > 0 {
+		ids, err := app.MibigModel.EntryIds(req.Accessions)
+		if err != nil {
+			app.serverError(c, err)
+			return
+		}
+		entry_ids = append(entry_ids, ids...)
+	}
+	if req.Query != nil {
+		ids, err := app.MibigModel.Search(req.Query.Terms)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		entry_ids = utils.UnionInt(entry_ids, ids)
+	}
+
+	entries, err := app.MibigModel.Entries(entry_ids)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	cited, err := export.CitedPublications(entries)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	ids := make([]string, 0, len(cited))
+	for id := range cited {
+		ids = append(ids, id)
+	}
+	publications, err := app.PublicationModel.Get(ids)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	var out bytes.Buffer
+	if err = export.WriteReferences(&out, export.BuildReferences(cited, publications), req.Format); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, out.Bytes())
+}
@@ -0,0 +1,154 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExportRdf(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name                string
+		Format              string
+		ExpectedStatus      int
+		ExpectedContentType string
+		ExpectedSnippet     string
+	}{
+		{Name: "default", Format: "", ExpectedStatus: http.StatusOK, ExpectedContentType: "text/turtle", ExpectedSnippet: "a schema:Gene"},
+		{Name: "turtle", Format: "turtle", ExpectedStatus: http.StatusOK, ExpectedContentType: "text/turtle", ExpectedSnippet: "@prefix schema: <https://schema.org/> ."},
+		{Name: "ntriples", Format: "ntriples", ExpectedStatus: http.StatusOK, ExpectedContentType: "application/n-triples",
+			ExpectedSnippet: "<https://mibig.secondarymetabolites.org/repository/BGC0000001/> <https://schema.org/taxonomicRange> <http://purl.obolibrary.org/obo/NCBITaxon_1901> ."},
+		{Name: "invalid", Format: "rdfxml", ExpectedStatus: http.StatusBadRequest, ExpectedContentType: "application/json; charset=utf-8", ExpectedSnippet: "Invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			url := ts.URL + "/api/v1/export/rdf"
+			if tt.Format != "" {
+				url += "?format=" + tt.Format
+			}
+			response, err := ts.Client().Get(url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.ExpectedStatus {
+				t.Errorf("Expected %d, got %d", tt.ExpectedStatus, response.StatusCode)
+			}
+
+			if contentType := response.Header.Get("Content-Type"); contentType != tt.ExpectedContentType {
+				t.Errorf("Expected content type %s, got %s", tt.ExpectedContentType, contentType)
+			}
+
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(body), tt.ExpectedSnippet) {
+				t.Errorf("Expected %q in response:\n%s", tt.ExpectedSnippet, string(body))
+			}
+		})
+	}
+}
+
+func TestExportCompounds(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name                string
+		Body                string
+		ExpectedStatus      int
+		ExpectedContentType string
+		ExpectedSnippet     string
+	}{
+		{Name: "all as smiles", Body: `{}`, ExpectedStatus: http.StatusOK, ExpectedContentType: "text/tab-separated-values",
+			ExpectedSnippet: "CC(=O)O\tBGC0000001\ttestomycin A\tC2H4O2\t60.052\tAntibacterial\tpubchem:176\n"},
+		{Name: "search as sdf", Body: `{"search_string": "NRP", "format": "sdf"}`, ExpectedStatus: http.StatusOK, ExpectedContentType: "chemical/x-mdl-sdfile",
+			ExpectedSnippet: "> <MIBIG_ACCESSION>\nBGC0000001\n"},
+		{Name: "invalid format", Body: `{"format": "mol2"}`, ExpectedStatus: http.StatusBadRequest, ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedSnippet: "Invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Post(ts.URL+"/api/v1/export/compounds", "application/json", strings.NewReader(tt.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.ExpectedStatus {
+				t.Errorf("Expected %d, got %d", tt.ExpectedStatus, response.StatusCode)
+			}
+
+			if contentType := response.Header.Get("Content-Type"); contentType != tt.ExpectedContentType {
+				t.Errorf("Expected content type %s, got %s", tt.ExpectedContentType, contentType)
+			}
+
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(body), tt.ExpectedSnippet) {
+				t.Errorf("Expected %q in response:\n%s", tt.ExpectedSnippet, string(body))
+			}
+		})
+	}
+}
+
+func TestExportReferences(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name                string
+		Body                string
+		ExpectedStatus      int
+		ExpectedContentType string
+		ExpectedSnippet     string
+	}{
+		{Name: "accessions as bibtex", Body: `{"accessions": ["BGC0000001"]}`, ExpectedStatus: http.StatusOK, ExpectedContentType: "application/x-bibtex",
+			ExpectedSnippet: "  title = {Testomycin biosynthesis \\& you},\n"},
+		{Name: "search as ris", Body: `{"search_string": "NRP", "format": "ris"}`, ExpectedStatus: http.StatusOK, ExpectedContentType: "application/x-research-info-systems",
+			ExpectedSnippet: "N1  - MIBiG: BGC0000001\n"},
+		{Name: "no selection", Body: `{"format": "ris"}`, ExpectedStatus: http.StatusBadRequest, ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedSnippet: "Need a list of accessions or a query"},
+		{Name: "invalid format", Body: `{"accessions": ["BGC0000001"], "format": "endnote"}`, ExpectedStatus: http.StatusBadRequest, ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedSnippet: "Invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Post(ts.URL+"/api/v1/export/references", "application/json", strings.NewReader(tt.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.ExpectedStatus {
+				t.Errorf("Expected %d, got %d", tt.ExpectedStatus, response.StatusCode)
+			}
+
+			if contentType := response.Header.Get("Content-Type"); contentType != tt.ExpectedContentType {
+				t.Errorf("Expected content type %s, got %s", tt.ExpectedContentType, contentType)
+			}
+
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(body), tt.ExpectedSnippet) {
+				t.Errorf("Expected %q in response:\n%s", tt.ExpectedSnippet, string(body))
+			}
+		})
+	}
+}
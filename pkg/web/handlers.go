@@ -1,14 +1,16 @@
 package web
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 
+	"secondarymetabolites.org/mibig-api/pkg/export"
 	"secondarymetabolites.org/mibig-api/pkg/models"
 	"secondarymetabolites.org/mibig-api/pkg/queries"
 )
@@ -59,7 +61,17 @@ func (app *application) stats(c *gin.Context) {
 		TaxonStats: taxon_stats,
 	}
 
-	c.JSON(http.StatusOK, &stat_info)
+	app.cachedJSON(c, &stat_info)
+}
+
+func (app *application) types(c *gin.Context) {
+	types, err := app.MibigModel.TypeTree()
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.cachedJSON(c, types)
 }
 
 func (app *application) repository(c *gin.Context) {
@@ -69,7 +81,42 @@ func (app *application) repository(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, repository_entries)
+	app.cachedJSON(c, repository_entries)
+}
+
+const MIMEJSONLD = "application/ld+json"
+
+func (app *application) entry(c *gin.Context) {
+	accession := c.Param("accession")
+
+	entry, err := app.MibigModel.GetEntry(accession)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, MIMEJSONLD) {
+	case MIMEJSONLD:
+		doc, err := export.EntryToJsonLD(entry)
+		if err != nil {
+			app.serverError(c, err)
+			return
+		}
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			app.serverError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, MIMEJSONLD, raw)
+	case gin.MIMEJSON:
+		c.JSON(http.StatusOK, entry.Data)
+	default:
+		app.clientError(c, http.StatusNotAcceptable)
+	}
 }
 
 type queryContainer struct {
@@ -205,11 +252,100 @@ func (app *application) Contributors(c *gin.Context) {
 	c.JSON(http.StatusOK, contributors)
 }
 
+func (app *application) contributor(c *gin.Context) {
+	profile, err := app.MibigModel.ContributorProfile(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
 type loginData struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+func maxLoginFailures() int {
+	if viper.IsSet("login.max_failures") {
+		return viper.GetInt("login.max_failures")
+	}
+	return 5
+}
+
+func lockoutDuration() time.Duration {
+	if viper.IsSet("login.lockout") {
+		return viper.GetDuration("login.lockout")
+	}
+	return 15 * time.Minute
+}
+
+// confirmPassword checks the password of the active account with the given email, applying the
+// brute-force protections of logins: the per-IP rate limit, account lockouts and counting failures.
+// If the password isn't accepted, the request is answered, using failStatus for wrong credentials.
+func (app *application) confirmPassword(c *gin.Context, email, password string, failStatus int) (*models.Submitter, bool) {
+	ip := app.clientIP(c)
+	if ok, retryAfter := app.LoginLimiter.Allow(rateLimitKey(ip)); !ok {
+		app.logger.Warnw("password check rate limited", "ip", ip, "email", email, "path", c.Request.URL.Path)
+		app.tooManyRequests(c, retryAfter)
+		return nil, false
+	}
+
+	// Check for a lockout before spending time on the password hash
+	known, err := app.SubmitterModel.Get(email, true)
+	if err != nil && !errors.Is(err, models.ErrNotFound) && !errors.Is(err, sql.ErrNoRows) {
+		app.serverError(c, err)
+		return nil, false
+	}
+	if known != nil {
+		lockout, err := app.LockoutModel.Get(known.Id)
+		if err != nil {
+			app.serverError(c, err)
+			return nil, false
+		}
+		if lockout.Locked(time.Now()) {
+			app.logger.Warnw("password check for locked account", "user", known.Id, "ip", ip, "path", c.Request.URL.Path)
+			app.tooManyRequests(c, time.Until(lockout.LockedUntil))
+			return nil, false
+		}
+	}
+
+	user, err := app.SubmitterModel.Authenticate(email, password)
+	if err != nil {
+		if !errors.Is(err, models.ErrInvalidCredentials) {
+			app.serverError(c, err)
+			return nil, false
+		}
+		app.logger.Warnw("password check failed", "email", email, "ip", ip, "path", c.Request.URL.Path)
+		if known != nil {
+			lockout, err := app.LockoutModel.RecordFailure(known.Id, maxLoginFailures(), lockoutDuration())
+			if err != nil {
+				app.serverError(c, err)
+				return nil, false
+			}
+			if lockout.Locked(time.Now()) {
+				app.logger.Warnw("account locked", "user", known.Id, "until", lockout.LockedUntil)
+			}
+		}
+		c.AbortWithStatus(failStatus)
+		return nil, false
+	}
+
+	if err = app.LockoutModel.Reset(user.Id); err != nil {
+		app.serverError(c, err)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// Login starts a session for valid credentials, see confirmPassword for the brute-force protections.
+// Users with two-factor authentication get a token for the second step at /login/2fa instead.
 func (app *application) Login(c *gin.Context) {
 	login := loginData{}
 	err := c.BindJSON(&login)
@@ -218,45 +354,40 @@ func (app *application) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := app.SubmitterModel.Authenticate(login.Email, login.Password)
-	if err != nil {
-		if errors.Is(err, models.ErrInvalidCredentials) {
-			c.AbortWithStatus(http.StatusUnauthorized)
-			return
-		} else {
-			app.logger.Error(err.Error())
-			c.AbortWithError(http.StatusInternalServerError, err)
-			return
-		}
-	}
-
-	expirationTime := time.Now().Add(2 * time.Hour)
-
-	claims := &Claims{
-		Name:  user.Name,
-		Email: user.Email,
-		Roles: models.RolesToStrings(user.Roles),
-		StandardClaims: jwt.StandardClaims{
-			Subject:   user.Id,
-			ExpiresAt: expirationTime.Unix(),
-			IssuedAt:  time.Now().Unix(),
-			Issuer:    viper.GetString("server.name"),
-		},
+	user, ok := app.confirmPassword(c, login.Email, login.Password, http.StatusUnauthorized)
+	if !ok {
+		return
 	}
+	ip := app.clientIP(c)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(viper.GetString("server.secret")))
+	twoFactor, err := app.twoFactorEnabled(user.Id)
 	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
+		app.serverError(c, err)
+		return
+	}
+	if twoFactor {
+		token, err := app.TokenModel.Generate(user.Email, models.TokenPurposeTwoFactor, twoFactorLoginLifetime)
+		if err != nil {
+			app.serverError(c, err)
+			return
+		}
+		app.logger.Infow("password accepted, awaiting second factor", "user", user.Id, "ip", ip)
+		c.JSON(http.StatusOK, twoFactorChallenge{TwoFactorRequired: true, TwoFactorToken: token.Token})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": tokenString, "call_name": user.CallName})
+	app.logger.Infow("login succeeded", "user", user.Id, "ip", ip)
+	app.startSession(c, user)
 }
 
+// Logout revokes the session the access token belongs to, invalidating its refresh token
 func (app *application) Logout(c *gin.Context) {
-	c.SetCookie("token", "", -1, "/", viper.GetString("server.name"), false, true)
-	c.AbortWithStatus(http.StatusNoContent)
+	claims := c.MustGet("claims").(*Claims)
+	if err := app.SessionModel.Revoke(claims.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
 }
 
 func (app *application) AuthTest(c *gin.Context) {
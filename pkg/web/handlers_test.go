@@ -7,13 +7,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/smtp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/viper"
 
 	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/cached"
 	"secondarymetabolites.org/mibig-api/pkg/models/mock"
 	"secondarymetabolites.org/mibig-api/pkg/queries"
 )
@@ -50,14 +54,33 @@ func newTestApp() (*application, *httptest.Server, *emailRecorder) {
 	mux := setupMux(true, logger.Desugar())
 
 	viper.Set("buildTime", "Fake time")
+	viper.Set("server.secret", "test secret")
+	keys, err := loadKeySet("", nil, "test secret")
+	if err != nil {
+		panic(err)
+	}
 	viper.Set("gitVer", "deadbeef")
 
+	mibigCache := cached.NewMibigModel(&mock.MibigModel{}, time.Minute, 10)
+	submitterModel := mock.NewSubmitterModel()
+
 	app := &application{
-		logger:      logger,
-		Mail:        sender,
-		MibigModel:  &mock.MibigModel{},
-		LegacyModel: &mock.LegacyModel{},
-		Mux:         mux,
+		logger:           logger,
+		Mail:             sender,
+		Keys:             keys,
+		LockoutModel:     &mock.LockoutModel{},
+		TwoFactorModel:   &mock.TwoFactorModel{},
+		AuditModel:       &mock.AuditModel{},
+		LoginLimiter:     newRateLimiter(20, time.Minute),
+		MibigModel:       mibigCache,
+		MibigCache:       mibigCache,
+		LegacyModel:      &mock.LegacyModel{},
+		PublicationModel: &mock.PublicationModel{},
+		SubmitterModel:   submitterModel,
+		TokenModel:       &mock.TokenModel{Submitters: submitterModel},
+		SessionModel:     mock.NewSessionModel(),
+		ApiKeyModel:      &mock.ApiKeyModel{},
+		Mux:              mux,
 	}
 	mux = app.routes()
 	mux.GET("/static/genes_form.html", func(c *gin.Context) {
@@ -69,6 +92,39 @@ func newTestApp() (*application, *httptest.Server, *emailRecorder) {
 	return app, ts, mail_rec
 }
 
+// newTestToken returns an access token for the mock submitter Alice
+func newTestToken(t *testing.T, roles ...string) string {
+	t.Helper()
+	return newUserToken(t, "AAAAAAAAAAAAAAAAAAAAAAAA", "BBBBBBBBBBBBBBBBBBBBBBBB", roles...)
+}
+
+// newAdminToken returns an access token for the mock admin
+func newAdminToken(t *testing.T) string {
+	t.Helper()
+	return newUserToken(t, "AAAAAAAAAAAAAAAAAAAAAAAB", "BBBBBBBBBBBBBBBBBBBBBBBC", "admin")
+}
+
+func newUserToken(t *testing.T, userId, sessionId string, roles ...string) string {
+	t.Helper()
+	viper.Set("server.secret", "test secret")
+	claims := &Claims{
+		Name:  "Alice",
+		Email: "alice@example.com",
+		Roles: roles,
+		StandardClaims: jwt.StandardClaims{
+			Id:        sessionId,
+			Subject:   userId,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
 func TestVersion(t *testing.T) {
 	_, ts, _ := newTestApp()
 	defer ts.Close()
@@ -131,6 +187,89 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestStatsRevalidation(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	response, err := ts.Client().Get(ts.URL + "/api/v1/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	etag := response.Header.Get("ETag")
+	lastModified := response.Header.Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("Expected ETag and Last-Modified headers, got %v", response.Header)
+	}
+
+	tests := []struct {
+		Name   string
+		Header string
+		Value  string
+		Status int
+	}{
+		{"matching etag", "If-None-Match", etag, http.StatusNotModified},
+		{"other etag", "If-None-Match", `"deadbeef"`, http.StatusOK},
+		{"not modified since", "If-Modified-Since", lastModified, http.StatusNotModified},
+		{"modified since", "If-Modified-Since", "Mon, 01 Jan 2001 00:00:00 GMT", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", ts.URL+"/api/v1/stats", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set(tt.Header, tt.Value)
+
+			response, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close()
+
+			if response.StatusCode != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestTypes(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	response, err := ts.Client().Get(ts.URL + "/api/v1/types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	var types []*models.BgcType
+	if err := json.Unmarshal(body, &types); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*models.BgcType{
+		{Term: "NRP", Description: "Nonribosomal peptide", Class: "nrps", Count: 2},
+		{Term: "PKS", Description: "Polyketide", Class: "pks", Count: 2, Children: []*models.BgcType{
+			{Term: "T1PKS", Description: "Type I PKS", Class: "pks", Count: 1},
+		}},
+	}
+	if !cmp.Equal(expected, types) {
+		t.Errorf("Unexpected types:\n%s", cmp.Diff(expected, types))
+	}
+}
+
 func TestRepository(t *testing.T) {
 	_, ts, _ := newTestApp()
 	defer ts.Close()
@@ -349,3 +488,173 @@ func TestContributors(t *testing.T) {
 		t.Errorf("Expected repository of length %d, got %d: %v", 1, len(contributors), contributors)
 	}
 }
+
+func TestContributor(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name     string
+		Id       string
+		Status   int
+		Expected *models.ContributorProfile
+	}{
+		{"public", "AAAAAAAAAAAAAAAAAAAAAAAA", http.StatusOK, &models.ContributorProfile{
+			Contributor: models.Contributor{Id: "AAAAAAAAAAAAAAAAAAAAAAAA", Name: "MIBiG Submitters",
+				Email: "mibig@example.com", Organisation: "MIBiG"},
+			Contributions: []models.Contribution{{Accession: "BGC0000001", Versions: []string{"2.0"}}},
+		}},
+		{"unknown or private", "BBBBBBBBBBBBBBBBBBBBBBBB", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Get(ts.URL + "/api/v1/contributors/" + tt.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+			if tt.Expected == nil {
+				return
+			}
+
+			var profile models.ContributorProfile
+			if err := json.Unmarshal(body, &profile); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.Expected, &profile) {
+				t.Errorf("Unexpected profile:\n%s", cmp.Diff(tt.Expected, &profile))
+			}
+		})
+	}
+}
+
+func TestEntry(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name                string
+		Accession           string
+		Accept              string
+		ExpectedStatus      int
+		ExpectedContentType string
+	}{
+		{Name: "json", Accession: "BGC0000001", Accept: "application/json", ExpectedStatus: http.StatusOK, ExpectedContentType: "application/json; charset=utf-8"},
+		{Name: "json-ld", Accession: "BGC0000001", Accept: "application/ld+json", ExpectedStatus: http.StatusOK, ExpectedContentType: "application/ld+json"},
+		{Name: "no accept header", Accession: "BGC0000001", Accept: "", ExpectedStatus: http.StatusOK, ExpectedContentType: "application/json; charset=utf-8"},
+		{Name: "missing", Accession: "BGC9999999", Accept: "application/ld+json", ExpectedStatus: http.StatusNotFound, ExpectedContentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", ts.URL+"/api/v1/entry/"+tt.Accession, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.Accept != "" {
+				req.Header.Set("Accept", tt.Accept)
+			}
+
+			response, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.ExpectedStatus {
+				t.Errorf("Expected %d, got %d", tt.ExpectedStatus, response.StatusCode)
+			}
+
+			if contentType := response.Header.Get("Content-Type"); contentType != tt.ExpectedContentType {
+				t.Errorf("Expected content type %s, got %s", tt.ExpectedContentType, contentType)
+			}
+
+			if tt.ExpectedStatus != http.StatusOK {
+				return
+			}
+
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var parsed map[string]interface{}
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := parsed["cluster"]; !ok && tt.Accept != "application/ld+json" {
+				t.Errorf("Expected raw entry data, got %v", parsed)
+			}
+			if _, ok := parsed["@context"]; !ok && tt.Accept == "application/ld+json" {
+				t.Errorf("Expected JSON-LD document, got %v", parsed)
+			}
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	viper.Set("login.max_failures", 3)
+	defer viper.Set("login.max_failures", 5)
+
+	attempt := func(password string) (int, http.Header) {
+		body := strings.NewReader(`{"email": "alice@example.com", "password": "` + password + `"}`)
+		response, err := ts.Client().Post(ts.URL+"/api/v1/login", "application/json", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		return response.StatusCode, response.Header
+	}
+
+	for i := 0; i < 3; i++ {
+		if status, _ := attempt("wrong"); status != http.StatusUnauthorized {
+			t.Fatalf("Expected %d for attempt %d, got %d", http.StatusUnauthorized, i+1, status)
+		}
+	}
+
+	status, header := attempt("secret")
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("Expected locked account to refuse the right password, got %d", status)
+	}
+	if header.Get("Retry-After") == "" {
+		t.Errorf("Expected Retry-After header")
+	}
+
+	if err := app.LockoutModel.Reset("AAAAAAAAAAAAAAAAAAAAAAAA"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := attempt("secret"); status != http.StatusOK {
+		t.Errorf("Expected unlocked account to log in, got %d", status)
+	}
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	app.LoginLimiter = newRateLimiter(2, time.Minute)
+
+	expected := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i, status := range expected {
+		body := strings.NewReader(`{"email": "mallory@example.com", "password": "guess"}`)
+		response, err := ts.Client().Post(ts.URL+"/api/v1/login", "application/json", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != status {
+			t.Errorf("Expected %d for attempt %d, got %d", status, i+1, response.StatusCode)
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package web
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	zap "go.uber.org/zap"
-	"net/http"
 )
 
 func (app *application) clientError(c *gin.Context, status int) {
@@ -18,3 +25,56 @@ func (app *application) serverError(c *gin.Context, err error) {
 func (app *application) notFound(c *gin.Context) {
 	app.clientError(c, http.StatusNotFound)
 }
+
+// tooManyRequests tells the client to retry after the given time, in whole seconds
+func (app *application) tooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+	app.clientError(c, http.StatusTooManyRequests)
+	c.Abort()
+}
+
+// cachedJSON serves obj as JSON with ETag and Last-Modified headers,
+// answering conditional requests with 304 Not Modified if the client's copy is current
+func (app *application) cachedJSON(c *gin.Context, obj interface{}) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+
+	var lastModified time.Time
+	if app.MibigCache != nil {
+		lastModified = app.MibigCache.LastModified().UTC().Truncate(time.Second)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if etagMatches(match, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		if !lastModified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, gin.MIMEJSON+"; charset=utf-8", body)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -4,13 +4,13 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 
 	"secondarymetabolites.org/mibig-api/pkg/models"
-	"secondarymetabolites.org/mibig-api/pkg/utils"
 )
 
 type Claims struct {
@@ -20,67 +20,260 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
-func (app *application) JWTAuthenticated(roles []models.Role) gin.HandlerFunc {
+// JWTAuthenticated accepts valid access tokens or API keys of users holding the required role or one ranked
+// above it, see models.RoleHierarchy. An empty role accepts all valid credentials.
+// Roles are looked up when the request is made, so changes apply to tokens issued before them.
+func (app *application) JWTAuthenticated(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		auth, err := getTokenFromHeader(c)
+		auth, scheme, err := getTokenFromHeader(c)
 		if err != nil {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
-		claims := &Claims{}
+		var claims *Claims
+		var roles []models.Role
 
-		token, err := jwt.ParseWithClaims(auth, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(viper.GetString("server.secret")), nil
-		})
+		if scheme == API_KEY_PREFIX {
+			claims, roles, err = app.checkApiKey(auth)
+			c.Set("apikey", true)
+		} else {
+			claims = &Claims{}
+			var token *jwt.Token
+			token, err = jwt.ParseWithClaims(auth, claims, app.Keys.keyFunc)
+
+			if err != nil {
+				var validationErr *jwt.ValidationError
+				rejected := jwt.ValidationErrorExpired | jwt.ValidationErrorSignatureInvalid | jwt.ValidationErrorUnverifiable
+				if errors.As(err, &validationErr) && validationErr.Errors&rejected != 0 {
+					c.AbortWithStatus(http.StatusUnauthorized)
+					return
+				}
+				app.logger.Debug(err.Error())
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+
+			if !token.Valid {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+
+			var user *models.Submitter
+			user, err = app.checkSession(claims)
+			if err == nil {
+				roles, err = app.effectiveRoles(user)
+			}
+		}
 
 		if err != nil {
-			if errors.Is(err, jwt.ErrSignatureInvalid) {
+			if errors.Is(err, models.ErrNotFound) {
 				c.AbortWithStatus(http.StatusUnauthorized)
 				return
 			}
-			app.logger.Debug(err.Error())
-			c.AbortWithStatus(http.StatusBadRequest)
+			app.serverError(c, err)
 			return
 		}
 
-		if !token.Valid {
+		c.Set("claims", claims)
+
+		if role != "" && !models.HasRole(roles, role) {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
-		c.Set("claims", claims)
+		c.Next()
+	}
+}
 
-		// If no roles are specified, accept all valid tokens
-		if len(roles) == 0 {
-			c.Next()
-			return
+// checkApiKey returns claims for the key's user along with the roles the key grants, the key's scopes
+// the user still holds and may use without two-factor authentication. Unknown or expired keys and inactive users give ErrNotFound.
+func (app *application) checkApiKey(secret string) (*Claims, []models.Role, error) {
+	key, err := app.ApiKeyModel.Authenticate(secret)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			return nil, nil, models.ErrNotFound
 		}
+		return nil, nil, err
+	}
+
+	user, err := app.SubmitterModel.GetById(key.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.Active {
+		return nil, nil, models.ErrNotFound
+	}
+
+	userRoles, err := app.effectiveRoles(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []models.Role
+	for _, role := range userRoles {
+		for _, scope := range key.Scopes {
+			if role.Name == scope {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	claims := &Claims{
+		Name:  user.Name,
+		Email: user.Email,
+		Roles: models.RolesToStrings(roles),
+		StandardClaims: jwt.StandardClaims{
+			Subject: user.Id,
+		},
+	}
+	return claims, roles, nil
+}
+
+// checkSession returns the token's user, or ErrNotFound if the token's session was revoked
+// or its user was deactivated since it was issued
+func (app *application) checkSession(claims *Claims) (*models.Submitter, error) {
+	session, err := app.SessionModel.Get(claims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserId != claims.Subject {
+		return nil, models.ErrNotFound
+	}
+
+	user, err := app.SubmitterModel.GetById(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, models.ErrNotFound
+	}
+	return user, nil
+}
+
+func accessTokenLifetime() time.Duration {
+	if viper.IsSet("server.access_token_lifetime") {
+		return viper.GetDuration("server.access_token_lifetime")
+	}
+	return 15 * time.Minute
+}
+
+func refreshTokenLifetime() time.Duration {
+	if viper.IsSet("server.refresh_token_lifetime") {
+		return viper.GetDuration("server.refresh_token_lifetime")
+	}
+	return 30 * 24 * time.Hour
+}
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	CallName     string `json:"call_name"`
+	// TwoFactorEnrolmentRequired is set if some of the user's roles are withheld until two-factor authentication is enabled
+	TwoFactorEnrolmentRequired bool `json:"two_factor_enrolment_required,omitempty"`
+}
 
-		validRoles := utils.IntersectString(models.RolesToStrings(roles), claims.Roles)
+// respondWithTokens signs a short-lived access token for the session and returns it along with the refresh token
+func (app *application) respondWithTokens(c *gin.Context, user *models.Submitter, session *models.Session, refreshToken string) {
+	roles, err := app.effectiveRoles(user)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
 
-		if len(validRoles) == 0 {
+	lifetime := accessTokenLifetime()
+	claims := &Claims{
+		Name:  user.Name,
+		Email: user.Email,
+		Roles: models.RolesToStrings(roles),
+		StandardClaims: jwt.StandardClaims{
+			Id:        session.Id,
+			Subject:   user.Id,
+			ExpiresAt: time.Now().Add(lifetime).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Issuer:    viper.GetString("server.name"),
+		},
+	}
+
+	tokenString, err := app.Keys.sign(claims)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse{
+		Token:                      tokenString,
+		RefreshToken:               refreshToken,
+		ExpiresIn:                  int(lifetime.Seconds()),
+		CallName:                   user.CallName,
+		TwoFactorEnrolmentRequired: len(roles) != len(user.Roles),
+	})
+}
+
+func (app *application) startSession(c *gin.Context, user *models.Submitter) {
+	session, refreshToken, err := app.SessionModel.Create(user.Id, refreshTokenLifetime())
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	app.respondWithTokens(c, user, session, refreshToken)
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// refreshToken exchanges a refresh token for a new access token and a new refresh token
+func (app *application) refreshToken(c *gin.Context) {
+	var req refreshRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	session, refreshToken, err := app.SessionModel.Refresh(req.RefreshToken, refreshTokenLifetime())
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
+		app.serverError(c, err)
+		return
+	}
 
-		c.Next()
-
+	user, err := app.SubmitterModel.GetById(session.UserId)
+	if err == nil && !user.Active {
+		err = models.ErrNotFound
 	}
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			app.SessionModel.Revoke(session.Id)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	app.respondWithTokens(c, user, session, refreshToken)
 }
 
 const HEADER_PREFIX string = "Bearer "
+const API_KEY_PREFIX string = "ApiKey "
 
-func getTokenFromHeader(c *gin.Context) (string, error) {
+// getTokenFromHeader returns the credentials from the Authorization header and the scheme they were sent with
+func getTokenFromHeader(c *gin.Context) (string, string, error) {
 	header := c.GetHeader("Authorization")
 	if header == "" {
-		return "", models.ErrNoCredentails
-	}
-	if !strings.HasPrefix(header, HEADER_PREFIX) {
-		return "", models.ErrNoCredentails
+		return "", "", models.ErrNoCredentails
 	}
 
-	header = strings.TrimPrefix(header, HEADER_PREFIX)
+	for _, prefix := range []string{HEADER_PREFIX, API_KEY_PREFIX} {
+		if strings.HasPrefix(header, prefix) {
+			return strings.TrimPrefix(header, prefix), prefix, nil
+		}
+	}
 
-	return header, nil
+	return "", "", models.ErrNoCredentails
 }
@@ -0,0 +1,90 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func login(t *testing.T, url string, client *http.Client, body string) tokenResponse {
+	t.Helper()
+	status, data := authenticatedRequest(t, client, "POST", url, "", strings.NewReader(body))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var tokens tokenResponse
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Fatalf("Expected access and refresh tokens, got %s", data)
+	}
+	return tokens
+}
+
+func TestRefreshToken(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	first := login(t, ts.URL+"/api/v1/login", ts.Client(), `{"email": "alice@example.com", "password": "secret"}`)
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", first.Token, nil); status != http.StatusOK {
+		t.Errorf("Expected access token to work, got %d", status)
+	}
+
+	second := login(t, ts.URL+"/api/v1/token/refresh", ts.Client(), `{"refresh_token": "`+first.RefreshToken+`"}`)
+	if second.RefreshToken == first.RefreshToken {
+		t.Errorf("Expected refresh token to be rotated")
+	}
+
+	// Reusing a rotated refresh token revokes the whole session
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/token/refresh", "", strings.NewReader(`{"refresh_token": "`+first.RefreshToken+`"}`))
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected %d for reused refresh token, got %d", http.StatusUnauthorized, status)
+	}
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", second.Token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected access token of revoked session to fail, got %d", status)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tokens := login(t, ts.URL+"/api/v1/login", ts.Client(), `{"email": "alice@example.com", "password": "secret"}`)
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/logout", tokens.Token, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected %d, got %d", http.StatusNoContent, status)
+	}
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", tokens.Token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected access token to be revoked, got %d", status)
+	}
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/token/refresh", "", strings.NewReader(`{"refresh_token": "`+tokens.RefreshToken+`"}`))
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected refresh token to be revoked, got %d", status)
+	}
+}
+
+func TestDeactivatedUser(t *testing.T) {
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "submitter")
+
+	user, err := app.SubmitterModel.GetById("AAAAAAAAAAAAAAAAAAAAAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Active = false
+	if err = app.SubmitterModel.Update(user, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected token of deactivated user to fail, got %d", status)
+	}
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/token/refresh", "", strings.NewReader(`{"refresh_token": "BBBBBBBBBBBBBBBBBBBBBBBB.secret"}`))
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected refresh for deactivated user to fail, got %d", status)
+	}
+}
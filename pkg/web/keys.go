@@ -0,0 +1,260 @@
+package web
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// signingMethodEdDSA implements Ed25519 signatures (RFC 8037), which jwt-go doesn't support
+type signingMethodEdDSA struct{}
+
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+// signingKey is an asymmetric key tokens are signed or verified with, identified by its JWK thumbprint
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// keySet holds the key new tokens are signed with and all keys tokens are accepted from.
+// Without asymmetric keys, tokens are signed with the shared secret using HS256.
+type keySet struct {
+	signing      *signingKey
+	verification map[string]*signingKey
+	secret       []byte
+}
+
+// loadKeySet reads PEM encoded RSA or Ed25519 keys. The signing key has to be a private key,
+// verification keys may be public keys, e.g. of keys rotated out but still used by unexpired tokens.
+// If secret isn't empty, HS256 tokens without a key id are accepted as well.
+func loadKeySet(signingPath string, verificationPaths []string, secret string) (*keySet, error) {
+	keys := keySet{
+		verification: make(map[string]*signingKey),
+		secret:       []byte(secret),
+	}
+
+	if signingPath != "" {
+		key, err := readKeyFile(signingPath)
+		if err != nil {
+			return nil, err
+		}
+		if key.private == nil {
+			return nil, fmt.Errorf("signing key %s is not a private key", signingPath)
+		}
+		keys.signing = key
+		keys.verification[key.id] = key
+	} else if secret == "" {
+		return nil, errors.New("neither a signing key nor a secret is configured")
+	}
+
+	for _, path := range verificationPaths {
+		key, err := readKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys.verification[key.id] = key
+	}
+
+	return &keys, nil
+}
+
+// loadConfiguredKeySet loads the keys from the server configuration. Once server.signing_key is set,
+// tokens signed with server.secret are only accepted while server.accept_secret_tokens is set,
+// so the shared secret can't be used to forge tokens after the switch to asymmetric keys.
+func loadConfiguredKeySet() (*keySet, error) {
+	signingPath := viper.GetString("server.signing_key")
+	secret := viper.GetString("server.secret")
+	if signingPath != "" && !viper.GetBool("server.accept_secret_tokens") {
+		secret = ""
+	}
+	return loadKeySet(signingPath, viper.GetStringSlice("server.verification_keys"), secret)
+}
+
+func readKeyFile(path string) (*signingKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", path, err)
+	}
+
+	key := signingKey{}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T in %s", parsed, path)
+	}
+	key.id = thumbprint(key.public)
+
+	return &key, nil
+}
+
+// jwk is the JSON Web Key representation of a public key (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// thumbprint returns the RFC 7638 JWK thumbprint of a public key
+func thumbprint(public interface{}) string {
+	var canonical string
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, encodeBigInt(big.NewInt(int64(k.E))), encodeBigInt(k.N))
+	case ed25519.PublicKey:
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(k))
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (k *signingKey) jwk() jwk {
+	key := jwk{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = encodeBigInt(public.N)
+		key.E = encodeBigInt(big.NewInt(int64(public.E)))
+	case ed25519.PublicKey:
+		key.Kty = "OKP"
+		key.Crv = "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return key
+}
+
+// sign returns the signed token for claims, using the signing key if there is one
+func (k *keySet) sign(claims jwt.Claims) (string, error) {
+	if k.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+	return token.SignedString(k.signing.private)
+}
+
+// keyFunc picks the verification key for a token by its key id,
+// making sure the token's algorithm matches the key's
+func (k *keySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		if token.Method == jwt.SigningMethodHS256 && len(k.secret) > 0 {
+			return k.secret, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	key, ok := k.verification[kid]
+	if !ok || key.method.Alg() != token.Method.Alg() {
+		return nil, ErrUnknownKey
+	}
+	return key.public, nil
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwks publishes the public keys tokens are verified with, so other services can validate tokens
+func (app *application) jwks(c *gin.Context) {
+	keys := make([]jwk, 0, len(app.Keys.verification))
+	for _, key := range app.Keys.verification {
+		keys = append(keys, key.jwk())
+	}
+	// Current signing key first, the rest in a stable order
+	sort.Slice(keys, func(i, j int) bool {
+		if app.Keys.signing != nil && (keys[i].Kid == app.Keys.signing.id) != (keys[j].Kid == app.Keys.signing.id) {
+			return keys[i].Kid == app.Keys.signing.id
+		}
+		return keys[i].Kid < keys[j].Kid
+	})
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, jwkSet{Keys: keys})
+}
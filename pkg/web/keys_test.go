@@ -0,0 +1,259 @@
+package web
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+// writeKeys writes the PEM encoded private and public key to dir, returning their paths
+func writeKeys(t *testing.T, dir, name string, private interface{}, public interface{}) (string, string) {
+	t.Helper()
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicBytes, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err = ioutil.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func testKeyFiles(t *testing.T) (string, string, string, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mibig-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPrivate, rsaPublic := writeKeys(t, dir, "rsa", rsaKey, &rsaKey.PublicKey)
+	edPrivatePath, edPublicPath := writeKeys(t, dir, "ed25519", edPrivate, edPublic)
+	return rsaPrivate, rsaPublic, edPrivatePath, edPublicPath, func() { os.RemoveAll(dir) }
+}
+
+func testClaims() *Claims {
+	return &Claims{
+		Name: "Alice",
+		StandardClaims: jwt.StandardClaims{
+			Subject:   "AAAAAAAAAAAAAAAAAAAAAAAA",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	rsaPrivate, rsaPublic, edPrivate, edPublic, cleanup := testKeyFiles(t)
+	defer cleanup()
+
+	tests := []struct {
+		Name    string
+		Signing string
+		Public  string
+		Alg     string
+	}{
+		{"RSA", rsaPrivate, rsaPublic, "RS256"},
+		{"Ed25519", edPrivate, edPublic, "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			signer, err := loadKeySet(tt.Signing, nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			signed, err := signer.sign(testClaims())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// A companion service only holding the public key
+			verifier, err := loadKeySet("", []string{tt.Public}, "unused")
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := jwt.ParseWithClaims(signed, &Claims{}, verifier.keyFunc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.Method.Alg() != tt.Alg {
+				t.Errorf("Expected %s, got %s", tt.Alg, token.Method.Alg())
+			}
+			if token.Header["kid"] != signer.signing.id {
+				t.Errorf("Expected kid %s, got %v", signer.signing.id, token.Header["kid"])
+			}
+		})
+	}
+
+	if _, err := loadKeySet(rsaPublic, nil, ""); err == nil {
+		t.Errorf("Expected public key to be rejected as signing key")
+	}
+	if _, err := loadKeySet("", nil, ""); err == nil {
+		t.Errorf("Expected error without any key")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	rsaPrivate, rsaPublic, edPrivate, _, cleanup := testKeyFiles(t)
+	defer cleanup()
+
+	old, err := loadKeySet(rsaPrivate, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := old.sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hsToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := loadKeySet(edPrivate, []string{rsaPublic}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.ParseWithClaims(oldToken, &Claims{}, rotated.keyFunc); err != nil {
+		t.Errorf("Expected token of previous key to be accepted, got %s", err)
+	}
+	if _, err = jwt.ParseWithClaims(hsToken, &Claims{}, rotated.keyFunc); err == nil {
+		t.Errorf("Expected HS256 token to be rejected without a secret")
+	}
+
+	retired, err := loadKeySet(edPrivate, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.ParseWithClaims(oldToken, &Claims{}, retired.keyFunc); err == nil {
+		t.Errorf("Expected token of retired key to be rejected")
+	}
+
+	// Tokens claiming another algorithm than their key's are rejected
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = old.signing.id
+	forgedToken, err := forged.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.ParseWithClaims(forgedToken, &Claims{}, rotated.keyFunc); err == nil {
+		t.Errorf("Expected token with mismatching algorithm to be rejected")
+	}
+}
+
+func TestConfiguredKeySet(t *testing.T) {
+	rsaPrivate, _, _, _, cleanup := testKeyFiles(t)
+	defer cleanup()
+	viper.Set("server.signing_key", rsaPrivate)
+	defer viper.Set("server.signing_key", "")
+	defer viper.Set("server.accept_secret_tokens", false)
+
+	hsToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString([]byte("test secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name     string
+		Accept   bool
+		Accepted bool
+	}{
+		{"secret tokens rejected", false, false},
+		{"secret tokens accepted during transition", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			viper.Set("server.accept_secret_tokens", tt.Accept)
+			keys, err := loadConfiguredKeySet()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = jwt.ParseWithClaims(hsToken, &Claims{}, keys.keyFunc)
+			if (err == nil) != tt.Accepted {
+				t.Errorf("Expected HS256 token accepted %t, got error %v", tt.Accepted, err)
+			}
+		})
+	}
+}
+
+func TestJwks(t *testing.T) {
+	rsaPrivate, _, edPrivate, edPublic, cleanup := testKeyFiles(t)
+	defer cleanup()
+
+	app, ts, _ := newTestApp()
+	defer ts.Close()
+
+	keys, err := loadKeySet(rsaPrivate, []string{edPublic}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Keys = keys
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/.well-known/jwks.json", "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var set jwkSet
+	if err = json.Unmarshal(body, &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(set.Keys))
+	}
+	if set.Keys[0].Kid != keys.signing.id || set.Keys[0].Kty != "RSA" || set.Keys[0].E != "AQAB" {
+		t.Errorf("Expected signing key first, got %+v", set.Keys[0])
+	}
+
+	edKeys, err := loadKeySet(edPrivate, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Keys[1].Kid != edKeys.signing.id || set.Keys[1].Crv != "Ed25519" || set.Keys[1].Alg != "EdDSA" {
+		t.Errorf("Unexpected verification key %+v", set.Keys[1])
+	}
+
+	// Access tokens issued with the asymmetric key are accepted by the API
+	status, body = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/login", "", strings.NewReader(`{"email": "alice@example.com", "password": "secret"}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var tokens tokenResponse
+	if err = json.Unmarshal(body, &tokens); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ = authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/authtest", tokens.Token, nil); status != http.StatusOK {
+		t.Errorf("Expected RS256 token to be accepted, got %d", status)
+	}
+}
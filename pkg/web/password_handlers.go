@@ -0,0 +1,144 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const passwordResetLifetime = time.Hour
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// forgotPassword mails a password reset token. To not reveal which addresses have an
+// account, it always answers 204 No Content.
+func (app *application) forgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.BindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, queryError{Message: "email is required", Error: true})
+		return
+	}
+
+	// Accounts that are not verified or were deactivated can't reset their password
+	if _, err := app.SubmitterModel.Get(req.Email, true); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, sql.ErrNoRows) {
+			app.logger.Errorw("failed to look up user for password reset", "error", err)
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	token, err := app.TokenModel.Generate(req.Email, models.TokenPurposePasswordReset, passwordResetLifetime)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			app.logger.Errorw("failed to generate password reset token", "error", err)
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err = app.Mail.SendTo(req.Email, generatePasswordResetMailBody(req.Email, app.Mail.Config().Recipient, token)); err != nil {
+		app.logger.Errorw("failed to send password reset mail", "error", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func passwordResetURL(token string) string {
+	base := viper.GetString("server.password_reset_url")
+	if base == "" {
+		base = fmt.Sprintf("https://%s/reset-password", viper.GetString("server.name"))
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+func generatePasswordResetMailBody(to, from string, token *models.Token) []byte {
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: MIBiG password reset\r\n\r\n"+
+		"Someone requested a password reset for your MIBiG account.\n\n"+
+		"To choose a new password, visit\n  %s\n\n"+
+		"The link is valid until %s and can only be used once.\n"+
+		"If you did not request a password reset, you can ignore this mail.\n",
+		from, to, passwordResetURL(token.Token), token.Expires.UTC().Format(time.RFC1123)))
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (app *application) resetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if len(req.Password) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, queryError{Message: "new password is too short", Error: true})
+		return
+	}
+
+	userId, err := app.TokenModel.Validate(req.Token, models.TokenPurposePasswordReset)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	// Remove the token first, so concurrent requests can't use it twice
+	if err = app.TokenModel.Remove(req.Token); err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	if err = app.SubmitterModel.ChangePassword(userId, req.Password); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err = app.SessionModel.RevokeAll(userId); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err = app.LockoutModel.Reset(userId); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(userId, models.AuditUserPassword, userId, nil, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// expireTokens periodically removes expired tokens and sessions, and forgets old login attempts
+func (app *application) expireTokens(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := app.TokenModel.Expire(); err != nil {
+			app.logger.Errorw("failed to expire tokens", "error", err)
+		}
+		if err := app.SessionModel.Expire(); err != nil {
+			app.logger.Errorw("failed to expire sessions", "error", err)
+		}
+		app.LoginLimiter.Prune()
+	}
+}
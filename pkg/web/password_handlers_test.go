@@ -0,0 +1,65 @@
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPasswordReset(t *testing.T) {
+	app, ts, mail := newTestApp()
+	defer ts.Close()
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/password/forgot", "", strings.NewReader(`{"email": "mallory@example.com"}`))
+	if status != http.StatusNoContent {
+		t.Errorf("Expected %d for unknown email, got %d", http.StatusNoContent, status)
+	}
+	if mail.msg != nil {
+		t.Errorf("Expected no mail for unknown email, got %s", mail.msg)
+	}
+
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/password/forgot", "", strings.NewReader(`{"email": "alice@example.com"}`))
+	if status != http.StatusNoContent {
+		t.Fatalf("Expected %d, got %d", http.StatusNoContent, status)
+	}
+	if !cmp.Equal([]string{"alice@example.com"}, mail.to) {
+		t.Fatalf("Expected reset mail to alice@example.com, got %v", mail.to)
+	}
+
+	match := regexp.MustCompile(`token=(\S+)`).FindSubmatch(mail.msg)
+	if match == nil {
+		t.Fatalf("No reset link in mail:\n%s", mail.msg)
+	}
+	token, err := url.QueryUnescape(string(match[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Status int
+	}{
+		{"invalid token", `{"token": "invalid", "password": "much more secret"}`, http.StatusBadRequest},
+		{"too short", `{"token": "` + token + `", "password": "short"}`, http.StatusBadRequest},
+		{"reset", `{"token": "` + token + `", "password": "much more secret"}`, http.StatusNoContent},
+		{"reused token", `{"token": "` + token + `", "password": "even more secret"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/password/reset", "", strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+		})
+	}
+
+	if _, err := app.SubmitterModel.Authenticate("alice@example.com", "much more secret"); err != nil {
+		t.Errorf("Expected new password to work, got %s", err)
+	}
+}
@@ -0,0 +1,171 @@
+package web
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaxRateLimitKeys bounds the memory used by a rateLimiter
+const defaultMaxRateLimitKeys = 100000
+
+// rateLimiter allows up to limit events per key within a sliding window, kept in memory
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	// maxKeys is the number of keys tracked at most. Once reached, the key with the oldest
+	// last event is forgotten to make room, so a flood of new keys can't lock out everyone else.
+	maxKeys int
+
+	mu     sync.Mutex
+	events map[string][]time.Time
+
+	// now can be replaced in tests
+	now func() time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		maxKeys: defaultMaxRateLimitKeys,
+		events:  make(map[string][]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Allow records an event for key, returning false and the time until the next event is allowed if over the limit
+func (l *rateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	events := l.recent(key, now)
+	if _, known := l.events[key]; !known && len(l.events) >= l.maxKeys {
+		l.prune(now)
+		if len(l.events) >= l.maxKeys {
+			l.evictOldest()
+		}
+	}
+	if len(events) >= l.limit {
+		l.events[key] = events
+		return false, events[0].Add(l.window).Sub(now)
+	}
+
+	l.events[key] = append(events, now)
+	return true, 0
+}
+
+// recent returns the events of key still within the window
+func (l *rateLimiter) recent(key string, now time.Time) []time.Time {
+	events := l.events[key]
+	start := 0
+	for start < len(events) && !events[start].After(now.Add(-l.window)) {
+		start++
+	}
+	return events[start:]
+}
+
+// Prune forgets keys without events in the current window
+func (l *rateLimiter) Prune() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(l.now())
+}
+
+func (l *rateLimiter) prune(now time.Time) {
+	for key := range l.events {
+		if events := l.recent(key, now); len(events) > 0 {
+			l.events[key] = events
+		} else {
+			delete(l.events, key)
+		}
+	}
+}
+
+// evictOldest forgets the key whose last event is the oldest
+func (l *rateLimiter) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, events := range l.events {
+		last := events[len(events)-1]
+		if oldestKey == "" || last.Before(oldest) {
+			oldestKey, oldest = key, last
+		}
+	}
+	delete(l.events, oldestKey)
+}
+
+// rateLimitKey returns the key to rate limit a client address by. IPv6 addresses are grouped
+// by their /64 prefix, as a single subscriber usually gets a whole /64 to pick addresses from.
+func rateLimitKey(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() != nil {
+		return address
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// parseTrustedProxies parses the IP addresses or CIDR ranges of reverse proxies allowed to set X-Forwarded-For
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * len(ip)
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %s", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func (app *application) trustedProxy(ip net.IP) bool {
+	for _, network := range app.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address a request came from. X-Forwarded-For is only used for requests
+// from a trusted proxy, taking the rightmost address that isn't one, as clients can send arbitrary values.
+func (app *application) clientIP(c *gin.Context) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		host = c.Request.RemoteAddr
+	}
+	if !app.trustedProxy(net.ParseIP(host)) {
+		return host
+	}
+
+	forwarded := strings.Split(c.GetHeader("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwarded[i])
+		ip := net.ParseIP(address)
+		if ip == nil {
+			break
+		}
+		if !app.trustedProxy(ip) {
+			return address
+		}
+	}
+	return host
+}
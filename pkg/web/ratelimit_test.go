@@ -0,0 +1,137 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute)
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("10.0.0.1"); !ok {
+			t.Fatalf("Expected event %d to be allowed", i+1)
+		}
+		now = now.Add(10 * time.Second)
+	}
+
+	ok, retry := limiter.Allow("10.0.0.1")
+	if ok {
+		t.Fatalf("Expected third event to be refused")
+	}
+	if retry != 40*time.Second {
+		t.Errorf("Expected retry after %s, got %s", 40*time.Second, retry)
+	}
+	if ok, _ := limiter.Allow("10.0.0.2"); !ok {
+		t.Errorf("Expected other keys to be unaffected")
+	}
+
+	now = now.Add(41 * time.Second)
+	if ok, _ := limiter.Allow("10.0.0.1"); !ok {
+		t.Errorf("Expected event to be allowed once the first one left the window")
+	}
+
+	now = now.Add(2 * time.Minute)
+	limiter.Prune()
+	if len(limiter.events) != 0 {
+		t.Errorf("Expected all keys to be pruned, got %d", len(limiter.events))
+	}
+}
+
+func TestRateLimiterMaxKeys(t *testing.T) {
+	limiter := newRateLimiter(1, time.Minute)
+	limiter.maxKeys = 2
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	for _, key := range []string{"10.0.0.1", "10.0.0.2"} {
+		if ok, _ := limiter.Allow(key); !ok {
+			t.Fatalf("Expected %s to be allowed", key)
+		}
+		now = now.Add(time.Second)
+	}
+	if ok, _ := limiter.Allow("10.0.0.3"); !ok {
+		t.Errorf("Expected new key to be allowed while the limiter is full")
+	}
+	if len(limiter.events) != 2 {
+		t.Errorf("Expected %d keys, got %d", 2, len(limiter.events))
+	}
+	if _, ok := limiter.events["10.0.0.1"]; ok {
+		t.Errorf("Expected the key with the oldest event to be evicted")
+	}
+	if ok, _ := limiter.Allow("10.0.0.2"); ok {
+		t.Errorf("Expected recent key to keep its events")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if ok, _ := limiter.Allow("10.0.0.4"); !ok {
+		t.Errorf("Expected new key to be allowed once old keys expired")
+	}
+	if len(limiter.events) != 1 {
+		t.Errorf("Expected expired keys to be pruned, got %d keys", len(limiter.events))
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		Address  string
+		Expected string
+	}{
+		{"203.0.113.5", "203.0.113.5"},
+		{"2001:db8:1:2:3:4:5:6", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2:ffff::1", "2001:db8:1:2::/64"},
+		{"::ffff:203.0.113.5", "::ffff:203.0.113.5"},
+		{"not an address", "not an address"},
+	}
+	for _, tt := range tests {
+		if key := rateLimitKey(tt.Address); key != tt.Expected {
+			t.Errorf("rateLimitKey(%q): expected %q, got %q", tt.Address, tt.Expected, key)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &application{TrustedProxies: proxies}
+
+	tests := []struct {
+		Name      string
+		Remote    string
+		Forwarded string
+		Expected  string
+	}{
+		{"direct", "203.0.113.5:4321", "", "203.0.113.5"},
+		{"spoofed header", "203.0.113.5:4321", "198.51.100.1", "203.0.113.5"},
+		{"trusted proxy", "10.0.0.1:4321", "198.51.100.1", "198.51.100.1"},
+		{"prepended by client", "10.0.0.1:4321", "198.51.100.99, 198.51.100.1", "198.51.100.1"},
+		{"proxy chain", "10.0.0.1:4321", "198.51.100.1, 192.168.1.1", "198.51.100.1"},
+		{"no header from proxy", "10.0.0.1:4321", "", "10.0.0.1"},
+		{"garbage header", "10.0.0.1:4321", "not an ip", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/v1/login", nil)
+			c.Request.RemoteAddr = tt.Remote
+			if tt.Forwarded != "" {
+				c.Request.Header.Set("X-Forwarded-For", tt.Forwarded)
+			}
+			if ip := app.clientIP(c); ip != tt.Expected {
+				t.Errorf("Expected %s, got %s", tt.Expected, ip)
+			}
+		})
+	}
+
+	if _, err := parseTrustedProxies([]string{"proxy.example.com"}); err == nil {
+		t.Errorf("Expected invalid proxy to be rejected")
+	}
+}
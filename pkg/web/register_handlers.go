@@ -0,0 +1,208 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+const emailVerificationLifetime = 48 * time.Hour
+
+type registrationRequest struct {
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	CallName    string `json:"call_name"`
+	Institution string `json:"institution"`
+	Password    string `json:"password"`
+	Public      bool   `json:"public"`
+	GDPRConsent bool   `json:"gdpr_consent"`
+}
+
+// register creates an inactive guest account and mails a verification link
+func (app *application) register(c *gin.Context) {
+	var req registrationRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	switch {
+	case !strings.Contains(req.Email, "@"):
+		c.JSON(http.StatusBadRequest, queryError{Message: "a valid email address is required", Error: true})
+		return
+	case req.Name == "":
+		c.JSON(http.StatusBadRequest, queryError{Message: "name is required", Error: true})
+		return
+	case len(req.Password) < minPasswordLength:
+		c.JSON(http.StatusBadRequest, queryError{Message: "password is too short", Error: true})
+		return
+	case !req.GDPRConsent:
+		c.JSON(http.StatusBadRequest, queryError{Message: "registration requires consent to storing your data", Error: true})
+		return
+	}
+
+	roles, err := app.SubmitterModel.GetRolesByName([]string{"guest"})
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	submitter := models.Submitter{
+		Email:       req.Email,
+		Name:        req.Name,
+		CallName:    req.CallName,
+		Institution: req.Institution,
+		Public:      req.Public,
+		GDPRConsent: true,
+		Active:      false,
+		Roles:       roles,
+	}
+
+	err = app.SubmitterModel.Insert(&submitter, req.Password)
+	if errors.Is(err, models.ErrDuplicateEmail) {
+		err = app.replacePendingRegistration(&submitter, req.Password)
+		if errors.Is(err, models.ErrDuplicateEmail) {
+			c.JSON(http.StatusConflict, queryError{Message: "an account with this email address already exists", Error: true})
+			return
+		}
+	}
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.audit(submitter.Id, models.AuditUserCreate, submitter.Id, nil, submitter.Audit())
+
+	token, err := app.TokenModel.Generate(submitter.Email, models.TokenPurposeEmailVerification, emailVerificationLifetime)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if err = app.Mail.SendTo(submitter.Email, generateVerificationMailBody(submitter.Email, app.Mail.Config().Recipient, token)); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// replacePendingRegistration replaces an account that was registered but never verified, so a lost
+// verification mail doesn't lock the address. Pending links of the old account stop working with it.
+// Other accounts with the same email address give ErrDuplicateEmail.
+func (app *application) replacePendingRegistration(submitter *models.Submitter, password string) error {
+	existing, err := app.SubmitterModel.Get(submitter.Email, false)
+	if err != nil {
+		return err
+	}
+	if existing.Active || existing.EmailVerified {
+		return models.ErrDuplicateEmail
+	}
+
+	if err = app.SubmitterModel.Delete(existing.Email); err != nil {
+		return err
+	}
+	app.audit(existing.Id, models.AuditUserDelete, existing.Id, existing.Audit(), nil)
+
+	return app.SubmitterModel.Insert(submitter, password)
+}
+
+func verificationURL(token string) string {
+	base := viper.GetString("server.verification_url")
+	if base == "" {
+		base = fmt.Sprintf("https://%s/verify", viper.GetString("server.name"))
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+func generateVerificationMailBody(to, from string, token *models.Token) []byte {
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Verify your MIBiG account\r\n\r\n"+
+		"Thank you for registering with MIBiG.\n\n"+
+		"To verify your email address, visit\n  %s\n\n"+
+		"The link is valid until %s.\n"+
+		"If you did not register, you can ignore this mail.\n",
+		from, to, verificationURL(token.Token), token.Expires.UTC().Format(time.RFC1123)))
+}
+
+func generateApprovalMailBody(user *models.Submitter, recipient string) []byte {
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: MIBiG account awaiting approval\r\n\r\n"+
+		"A new account has verified its email address and awaits approval.\n\n"+
+		"ID: %s\nName: %s\nEmail: %s\nInstitution: %s\n",
+		user.Email, recipient, user.Id, user.Name, user.Email, user.Institution))
+}
+
+type verificationRequest struct {
+	Token string `json:"token"`
+}
+
+type verificationResult struct {
+	Active bool `json:"active"`
+}
+
+// verifyEmail marks an account's email address as verified and activates it.
+// If registration.require_approval is set, the account stays inactive and the MIBiG team is notified instead.
+func (app *application) verifyEmail(c *gin.Context) {
+	var req verificationRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	userId, err := app.TokenModel.Validate(req.Token, models.TokenPurposeEmailVerification)
+	if err == nil {
+		err = app.TokenModel.Remove(req.Token)
+	}
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	user, err := app.SubmitterModel.GetById(userId)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	// Only pending registrations are activated, never accounts an admin handled since
+	if user.Active || user.EmailVerified {
+		c.JSON(http.StatusBadRequest, queryError{Message: "account was already verified", Error: true})
+		return
+	}
+
+	requireApproval := viper.GetBool("registration.require_approval")
+
+	before := user.Audit()
+	user.EmailVerified = true
+	user.Active = !requireApproval
+	if err = app.SubmitterModel.Update(user, ""); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	if requireApproval {
+		app.audit(user.Id, models.AuditUserUpdate, user.Id, before, user.Audit())
+		if err = app.Mail.Send(user.Email, generateApprovalMailBody(user, app.Mail.Config().Recipient)); err != nil {
+			app.serverError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, &verificationResult{Active: false})
+		return
+	}
+
+	app.audit(user.Id, models.AuditUserActivate, user.Id, before, user.Audit())
+
+	c.JSON(http.StatusOK, &verificationResult{Active: true})
+}
@@ -0,0 +1,197 @@
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+)
+
+func registerBob(t *testing.T, ts string, client *http.Client, mail *emailRecorder) string {
+	t.Helper()
+	body := `{"email": "bob@example.com", "name": "Bob User", "password": "long enough", "gdpr_consent": true}`
+	status, _ := authenticatedRequest(t, client, "POST", ts+"/api/v1/register", "", strings.NewReader(body))
+	if status != http.StatusAccepted {
+		t.Fatalf("Expected %d, got %d", http.StatusAccepted, status)
+	}
+	if !cmp.Equal([]string{"bob@example.com"}, mail.to) {
+		t.Fatalf("Expected verification mail to bob@example.com, got %v", mail.to)
+	}
+
+	match := regexp.MustCompile(`token=(\S+)`).FindSubmatch(mail.msg)
+	if match == nil {
+		t.Fatalf("No verification link in mail:\n%s", mail.msg)
+	}
+	token, err := url.QueryUnescape(string(match[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRegister(t *testing.T) {
+	_, ts, mail := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Status int
+	}{
+		{"no consent", `{"email": "bob@example.com", "name": "Bob User", "password": "long enough"}`, http.StatusBadRequest},
+		{"invalid email", `{"email": "bob", "name": "Bob User", "password": "long enough", "gdpr_consent": true}`, http.StatusBadRequest},
+		{"short password", `{"email": "bob@example.com", "name": "Bob User", "password": "short", "gdpr_consent": true}`, http.StatusBadRequest},
+		{"duplicate email", `{"email": "alice@example.com", "name": "Alice", "password": "long enough", "gdpr_consent": true}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register", "", strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+		})
+	}
+	if mail.msg != nil {
+		t.Errorf("Expected no mail for failed registrations, got %s", mail.msg)
+	}
+}
+
+func TestRegisterPending(t *testing.T) {
+	app, ts, mail := newTestApp()
+	defer ts.Close()
+
+	// Registering again replaces an account whose verification mail got lost
+	oldToken := registerBob(t, ts.URL, ts.Client(), mail)
+	token := registerBob(t, ts.URL, ts.Client(), mail)
+	if token == oldToken {
+		t.Fatalf("Expected a new verification link")
+	}
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register/verify", "", strings.NewReader(`{"token": "`+oldToken+`"}`))
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected link of the replaced account to be invalid, got %d", status)
+	}
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register/verify", "", strings.NewReader(`{"token": "`+token+`"}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	if _, err := app.SubmitterModel.Authenticate("bob@example.com", "long enough"); err != nil {
+		t.Errorf("Expected verified account to be active, got %s", err)
+	}
+
+	// Verified accounts are kept
+	body := `{"email": "bob@example.com", "name": "Mallory", "password": "long enough", "gdpr_consent": true}`
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register", "", strings.NewReader(body))
+	if status != http.StatusConflict {
+		t.Errorf("Expected %d, got %d", http.StatusConflict, status)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	app, ts, mail := newTestApp()
+	defer ts.Close()
+
+	token := registerBob(t, ts.URL, ts.Client(), mail)
+
+	if _, err := app.SubmitterModel.Authenticate("bob@example.com", "long enough"); err == nil {
+		t.Errorf("Expected unverified account to be inactive")
+	}
+
+	tests := []struct {
+		Name     string
+		Body     string
+		Status   int
+		Expected string
+	}{
+		{"invalid token", `{"token": "invalid"}`, http.StatusBadRequest, ""},
+		{"verify", `{"token": "` + token + `"}`, http.StatusOK, `{"active":true}`},
+		{"reused token", `{"token": "` + token + `"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register/verify", "", strings.NewReader(tt.Body))
+			if status != tt.Status {
+				t.Errorf("Expected %d, got %d", tt.Status, status)
+			}
+			if tt.Expected != "" && string(body) != tt.Expected {
+				t.Errorf("Expected %s, got %s", tt.Expected, body)
+			}
+		})
+	}
+
+	user, err := app.SubmitterModel.Authenticate("bob@example.com", "long enough")
+	if err != nil {
+		t.Fatalf("Expected verified account to be active, got %s", err)
+	}
+	if len(user.Roles) != 1 || user.Roles[0].Name != "guest" {
+		t.Errorf("Expected guest role, got %v", user.Roles)
+	}
+}
+
+func TestVerifyEmailDeactivated(t *testing.T) {
+	app, ts, mail := newTestApp()
+	defer ts.Close()
+
+	token := registerBob(t, ts.URL, ts.Client(), mail)
+	bob, err := app.SubmitterModel.Get("bob@example.com", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/admin/users/"+bob.Id+"/deactivate", newAdminToken(t), nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register/verify", "", strings.NewReader(`{"token": "`+token+`"}`))
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected %d, got %d", http.StatusBadRequest, status)
+	}
+	if _, err = app.SubmitterModel.Authenticate("bob@example.com", "long enough"); err == nil {
+		t.Errorf("Expected deactivated account to stay inactive")
+	}
+}
+
+func TestVerifyEmailApproval(t *testing.T) {
+	app, ts, mail := newTestApp()
+	defer ts.Close()
+
+	viper.Set("registration.require_approval", true)
+	defer viper.Set("registration.require_approval", false)
+
+	token := registerBob(t, ts.URL, ts.Client(), mail)
+
+	status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register/verify", "", strings.NewReader(`{"token": "`+token+`"}`))
+	if status != http.StatusAccepted {
+		t.Errorf("Expected %d, got %d", http.StatusAccepted, status)
+	}
+	if string(body) != `{"active":false}` {
+		t.Errorf("Expected inactive account, got %s", body)
+	}
+	if !cmp.Equal([]string{"alice@example.com"}, mail.to) {
+		t.Errorf("Expected approval mail to the MIBiG team, got %v", mail.to)
+	}
+	if _, err := app.SubmitterModel.Authenticate("bob@example.com", "long enough"); err == nil {
+		t.Errorf("Expected account awaiting approval to be inactive")
+	}
+	user, err := app.SubmitterModel.Get("bob@example.com", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.EmailVerified {
+		t.Errorf("Expected email address of account awaiting approval to be verified")
+	}
+
+	// Verified accounts awaiting approval aren't replaced by registering again
+	registration := `{"email": "bob@example.com", "name": "Mallory", "password": "long enough", "gdpr_consent": true}`
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/register", "", strings.NewReader(registration))
+	if status != http.StatusConflict {
+		t.Errorf("Expected %d, got %d", http.StatusConflict, status)
+	}
+}
@@ -2,30 +2,76 @@ package web
 
 import (
 	"github.com/gin-gonic/gin"
-
-	"secondarymetabolites.org/mibig-api/pkg/models"
 )
 
 func (app *application) routes() *gin.Engine {
+	app.Mux.GET("/.well-known/jwks.json", app.jwks)
+
 	api := app.Mux.Group("/api")
 	{
 		v1 := api.Group("/v1")
 		{
 			v1.GET("/version", app.version)
 			v1.GET("/stats", app.stats)
+			v1.GET("/stats/history", app.statsHistory)
+			v1.POST("/stats/crosstab", app.statsCrossTab)
+			v1.GET("/types", app.types)
 			v1.GET("/repository", app.repository)
+			v1.GET("/entry/:accession", app.entry)
 			v1.POST("/search", app.search)
+			v1.GET("/search/taxonomy-tree", app.taxonomyTree)
+			v1.GET("/taxonomy/:rank/:name", app.taxonomyNode)
+			v1.GET("/compound/:name", app.compound)
+			v1.GET("/compounds", app.compounds)
 			v1.GET("/available/:category/:term", app.available)
 			v1.GET("/convert", app.Convert)
 			v1.GET("/contributors", app.Contributors)
+			v1.GET("/contributors/:id", app.contributor)
+			v1.GET("/export/rdf", app.exportRdf)
+			v1.POST("/export/compounds", app.exportCompounds)
+			v1.POST("/export/references", app.exportReferences)
 
 			v1.POST("/login", app.Login)
-			v1.POST("/logout", app.Logout)
+			v1.POST("/login/2fa", app.loginTwoFactor)
+			v1.POST("/logout", app.JWTAuthenticated(""), app.Logout)
+			v1.POST("/token/refresh", app.refreshToken)
+			v1.POST("/register", app.register)
+			v1.POST("/register/verify", app.verifyEmail)
+			v1.POST("/password/forgot", app.forgotPassword)
+			v1.POST("/password/reset", app.resetPassword)
+
+			v1.GET("/authtest", app.JWTAuthenticated(""), app.AuthTest)
+			v1.POST("/submit", app.JWTAuthenticated("submitter"), app.submit)
+			v1.POST("/bgc-registration", app.JWTAuthenticated("submitter"), app.LegacyStoreSubmission)
+			v1.POST("/bgc-detail-registration", app.JWTAuthenticated("submitter"), app.LegacyStoreBgcDetailSubmission)
+
+			account := v1.Group("/account", app.JWTAuthenticated(""))
+			{
+				account.GET("", app.account)
+				account.PUT("", app.updateAccount)
+				account.POST("/password", app.changePassword)
+				account.GET("/data", app.accountData)
+				account.POST("/withdraw-consent", app.withdrawConsent)
+				account.POST("/erase", app.eraseAccount)
+				account.GET("/apikeys", app.listApiKeys)
+				account.POST("/apikeys", app.createApiKey)
+				account.DELETE("/apikeys/:id", app.revokeApiKey)
+				account.GET("/2fa", app.twoFactorStatus)
+				account.POST("/2fa/enrol", app.enrolTwoFactor)
+				account.POST("/2fa/confirm", app.confirmTwoFactor)
+				account.POST("/2fa/disable", app.disableTwoFactor)
+			}
 
-			v1.GET("/authtest", app.JWTAuthenticated([]models.Role{}), app.AuthTest)
-			v1.POST("/submit", app.JWTAuthenticated([]models.Role{}), app.submit)
-			v1.POST("/bgc-registration", app.JWTAuthenticated([]models.Role{}), app.LegacyStoreSubmission)
-			v1.POST("/bgc-detail-registration", app.JWTAuthenticated([]models.Role{}), app.LegacyStoreBgcDetailSubmission)
+			admin := v1.Group("/admin", app.JWTAuthenticated("admin"))
+			{
+				admin.POST("/cache/invalidate", app.invalidateCache)
+				admin.GET("/users", app.listUsers)
+				admin.GET("/users/:id", app.getUser)
+				admin.PUT("/users/:id/roles", app.setUserRoles)
+				admin.POST("/users/:id/activate", app.activateUser)
+				admin.POST("/users/:id/deactivate", app.deactivateUser)
+				admin.GET("/audit", app.listAudit)
+			}
 		}
 	}
 
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/queries"
+)
+
+func (app *application) statsHistory(c *gin.Context) {
+	history, err := app.MibigModel.ReleaseHistory()
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.cachedJSON(c, history)
+}
+
+type crossTabRequest struct {
+	Query        *queries.Query `json:"query"`
+	SearchString string         `json:"search_string"`
+	Rows         string         `json:"rows"`
+	Columns      string         `json:"columns"`
+}
+
+func (app *application) statsCrossTab(c *gin.Context) {
+	var req crossTabRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	var err error
+	if req.Query == nil && req.SearchString != "" {
+		req.Query, err = queries.NewQueryFromString(req.SearchString)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+	}
+
+	// Without a query, tabulate all entries
+	var entry_ids []int
+	if req.Query != nil {
+		entry_ids, err = app.MibigModel.Search(req.Query.Terms)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		if entry_ids == nil {
+			entry_ids = []int{}
+		}
+	}
+
+	table, err := app.MibigModel.CrossTab(entry_ids, req.Rows, req.Columns)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDimension) {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
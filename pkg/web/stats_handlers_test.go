@@ -0,0 +1,95 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestStatsHistory(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	response, err := ts.Client().Get(ts.URL + "/api/v1/stats/history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	var history []*models.ReleaseStats
+	if err := json.Unmarshal(body, &history); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*models.ReleaseStats{
+		{Version: "2.0", ReleaseCounts: models.ReleaseCounts{Migrated: 1},
+			ByClass:        map[string]*models.ReleaseCounts{"NRP": {Migrated: 1}},
+			ByCompleteness: map[string]*models.ReleaseCounts{"incomplete": {Migrated: 1}}},
+	}
+	if !cmp.Equal(expected, history) {
+		t.Errorf("Unexpected history:\n%s", cmp.Diff(expected, history))
+	}
+}
+
+func TestStatsCrossTab(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name     string
+		Body     string
+		Status   int
+		Expected *models.CrossTab
+	}{
+		{"class by phylum", `{"search_string": "NRP", "rows": "class", "columns": "phylum"}`, http.StatusOK, &models.CrossTab{
+			Rows:    []string{"NRP", "Polyketide"},
+			Columns: []string{"Actinobacteria", "Firmicutes"},
+			Data:    [][]int{{2, 0}, {0, 1}},
+		}},
+		{"invalid dimension", `{"rows": "class", "columns": "colour"}`, http.StatusBadRequest, nil},
+		{"invalid json", `{"rows": `, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Post(ts.URL+"/api/v1/stats/crosstab", "application/json", strings.NewReader(tt.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+			if tt.Expected == nil {
+				return
+			}
+
+			var table models.CrossTab
+			if err := json.Unmarshal(body, &table); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.Expected, &table) {
+				t.Errorf("Unexpected table:\n%s", cmp.Diff(tt.Expected, &table))
+			}
+		})
+	}
+}
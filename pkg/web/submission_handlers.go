@@ -20,9 +20,24 @@ func (app *application) submit(c *gin.Context) {
 		return
 	}
 
+	app.audit(actor(c), models.AuditSubmissionCreate, "accession request", nil, newSubmissionAudit(&req))
 	c.String(http.StatusAccepted, "")
 }
 
+// submissionAudit is what the audit log keeps of an accession request, leaving out the submitter's contact details
+type submissionAudit struct {
+	Compounds  []string `json:"compounds"`
+	Accessions []string `json:"accessions"`
+}
+
+func newSubmissionAudit(req *models.AccessionRequest) *submissionAudit {
+	accessions := make([]string, 0, len(req.Loci))
+	for _, locus := range req.Loci {
+		accessions = append(accessions, locus.GenBankAccession)
+	}
+	return &submissionAudit{Compounds: req.Compounds, Accessions: accessions}
+}
+
 func generateRequestMailBody(req *models.AccessionRequest, recipient string) []byte {
 	compound := strings.Join(req.Compounds, ", ")
 	var loci_parts []string
@@ -82,6 +97,8 @@ func (app *application) LegacyStoreSubmission(c *gin.Context) {
 		return
 	}
 
+	app.audit(actor(c), models.AuditSubmissionCreate, "legacy", nil, gin.H{"version": version})
+
 	c.Redirect(http.StatusSeeOther, "/static/genes_form.html")
 }
 
@@ -159,5 +176,6 @@ func (app *application) LegacyStoreBgcDetailSubmission(c *gin.Context) {
 		return
 	}
 
+	app.audit(actor(c), models.AuditSubmissionCreate, target+":"+bgc_id, nil, gin.H{"version": version})
 	c.AbortWithStatus(204)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/andreyvit/diff"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -12,6 +13,17 @@ import (
 	"testing"
 )
 
+// authenticatedPost works like http.Client.Post, sending token as bearer token
+func authenticatedPost(client *http.Client, url, contentType, token string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return client.Do(req)
+}
+
 func TestSubmit(t *testing.T) {
 	_, ts, mail_rec := newTestApp()
 	defer ts.Close()
@@ -44,7 +56,7 @@ func TestSubmit(t *testing.T) {
 	raw_req, err := json.Marshal(&req)
 	req_body := bytes.NewReader(raw_req)
 
-	response, err := ts.Client().Post(ts.URL+"/api/v1/submit", "application/json", req_body)
+	response, err := authenticatedPost(ts.Client(), ts.URL+"/api/v1/submit", "application/json", newTestToken(t, "submitter"), req_body)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -72,7 +84,7 @@ func TestLegacySubmission(t *testing.T) {
 	form.Set("json", `{"foo": "bar"}`)
 	form.Set("version", "1")
 
-	response, err := ts.Client().Post(ts.URL+"/api/v1/bgc-registration", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	response, err := authenticatedPost(ts.Client(), ts.URL+"/api/v1/bgc-registration", "application/x-www-form-urlencoded", newTestToken(t, "submitter"), strings.NewReader(form.Encode()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -97,7 +109,7 @@ func TestLegacyGeneSubmission(t *testing.T) {
 	form.Set("bgc_id", "BGC1234567")
 	form.Set("target", "gene_info")
 
-	response, err := ts.Client().Post(ts.URL+"/api/v1/bgc-detail-registration", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	response, err := authenticatedPost(ts.Client(), ts.URL+"/api/v1/bgc-detail-registration", "application/x-www-form-urlencoded", newTestToken(t, "submitter"), strings.NewReader(form.Encode()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -122,7 +134,7 @@ func TestLegacyNrpsSubmission(t *testing.T) {
 	form.Set("bgc_id", "BGC1234567")
 	form.Set("target", "nrps_info")
 
-	response, err := ts.Client().Post(ts.URL+"/api/v1/bgc-detail-registration", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	response, err := authenticatedPost(ts.Client(), ts.URL+"/api/v1/bgc-detail-registration", "application/x-www-form-urlencoded", newTestToken(t, "submitter"), strings.NewReader(form.Encode()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,73 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/queries"
+	"secondarymetabolites.org/mibig-api/pkg/taxonomy"
+)
+
+type taxonomyTreeResult struct {
+	Tree   *taxonomy.Node `json:"tree"`
+	Newick string         `json:"newick"`
+}
+
+func (app *application) taxonomyTree(c *gin.Context) {
+	var req struct {
+		Search string `form:"search_string"`
+	}
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	// Without a search string, build the tree for all entries
+	var entry_ids []int
+	if req.Search != "" {
+		query, err := queries.NewQueryFromString(req.Search)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+
+		entry_ids, err = app.MibigModel.Search(query.Terms)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		if entry_ids == nil {
+			entry_ids = []int{}
+		}
+	}
+
+	taxa, err := app.MibigModel.TaxonCounts(entry_ids)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	tree := taxonomy.BuildTree(taxa)
+	c.JSON(http.StatusOK, &taxonomyTreeResult{Tree: tree, Newick: tree.Newick()})
+}
+
+func (app *application) taxonomyNode(c *gin.Context) {
+	node, err := app.MibigModel.TaxonomyNode(c.Param("rank"), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRank) {
+			c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+			return
+		}
+		if errors.Is(err, models.ErrNotFound) {
+			app.notFound(c)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, node)
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+)
+
+func TestTaxonomyTree(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	response, err := ts.Client().Get(ts.URL + "/api/v1/search/taxonomy-tree?search_string=NRP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	var result taxonomyTreeResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Tree.Count != 3 {
+		t.Errorf("Expected %d entries in tree, got %d", 3, result.Tree.Count)
+	}
+
+	if len(result.Tree.Children) != 1 || result.Tree.Children[0].Name != "Bacteria" {
+		t.Errorf("Unexpected top level of tree: %v", result.Tree.Children)
+	}
+
+	expected := "((((((('Examplomyces exemplaris')Examplomyces)Streptomycetaceae)Streptomycetales)Actinobacteria)Actinobacteria," +
+		"((((('Lactococcus lactis')Lactococcus)Streptococcaceae)Lactobacillales)Bacilli)Firmicutes)Bacteria);"
+	if result.Newick != expected {
+		t.Errorf("Unexpected Newick string:\nwant %s\ngot  %s", expected, result.Newick)
+	}
+}
+
+func TestTaxonomyNode(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+
+	tests := []struct {
+		Name     string
+		Url      string
+		Status   int
+		Children int
+		Entries  []string
+	}{
+		{"genus", "/api/v1/taxonomy/genus/Examplomyces", http.StatusOK, 1, []string{}},
+		{"species", "/api/v1/taxonomy/species/Examplomyces%20exemplaris", http.StatusOK, 0, []string{"BGC0000001"}},
+		{"unknown taxon", "/api/v1/taxonomy/genus/Streptomyces", http.StatusNotFound, 0, nil},
+		{"invalid rank", "/api/v1/taxonomy/tribe/Examplomyceae", http.StatusBadRequest, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			response, err := ts.Client().Get(ts.URL + tt.Url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, response.StatusCode)
+			}
+			if tt.Status != http.StatusOK {
+				return
+			}
+
+			var node models.TaxonomyNode
+			if err := json.Unmarshal(body, &node); err != nil {
+				t.Fatal(err)
+			}
+			if len(node.Lineage) == 0 || node.Lineage[0].Name != "Bacteria" {
+				t.Errorf("Unexpected lineage: %v", node.Lineage)
+			}
+			if len(node.Children) != tt.Children {
+				t.Errorf("Expected %d children, got %v", tt.Children, node.Children)
+			}
+			if !cmp.Equal(tt.Entries, node.Entries) {
+				t.Errorf("Unexpected entries:\n%s", cmp.Diff(tt.Entries, node.Entries))
+			}
+		})
+	}
+}
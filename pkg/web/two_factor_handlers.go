@@ -0,0 +1,368 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/totp"
+	"secondarymetabolites.org/mibig-api/pkg/utils"
+)
+
+const (
+	twoFactorIssuer         = "MIBiG"
+	twoFactorLoginLifetime  = 5 * time.Minute
+	numberOfRecoveryCodes   = 10
+	recoveryCodeRandomBytes = 5
+)
+
+// twoFactorRequiredRoles returns the roles that are only granted to users with two-factor authentication.
+// Roles ranked above them are included, see models.HasRole.
+func twoFactorRequiredRoles() []string {
+	return viper.GetStringSlice("two_factor.required_roles")
+}
+
+// requiresTwoFactor reports whether role is covered by the two-factor policy
+func requiresTwoFactor(role models.Role) bool {
+	for _, required := range twoFactorRequiredRoles() {
+		if models.HasRole([]models.Role{role}, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveRoles returns the user's roles, minus those requiring two-factor authentication
+// if the user hasn't enabled it
+func (app *application) effectiveRoles(user *models.Submitter) ([]models.Role, error) {
+	var unrestricted []models.Role
+	for _, role := range user.Roles {
+		if !requiresTwoFactor(role) {
+			unrestricted = append(unrestricted, role)
+		}
+	}
+	if len(unrestricted) == len(user.Roles) {
+		return user.Roles, nil
+	}
+
+	enabled, err := app.twoFactorEnabled(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return user.Roles, nil
+	}
+	return unrestricted, nil
+}
+
+func (app *application) twoFactorEnabled(userId string) (bool, error) {
+	twoFactor, err := app.TwoFactorModel.Get(userId)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return twoFactor.Confirmed, nil
+}
+
+type twoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorToken    string `json:"two_factor_token"`
+}
+
+type twoFactorLogin struct {
+	TwoFactorToken string `json:"two_factor_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// loginTwoFactor is the second login step for users with two-factor authentication,
+// exchanging the token from the first step and a TOTP or recovery code for a session
+func (app *application) loginTwoFactor(c *gin.Context) {
+	var req twoFactorLogin
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	ip := app.clientIP(c)
+	if ok, retryAfter := app.LoginLimiter.Allow(rateLimitKey(ip)); !ok {
+		app.logger.Warnw("login rate limited", "ip", ip)
+		app.tooManyRequests(c, retryAfter)
+		return
+	}
+
+	userId, err := app.TokenModel.Validate(req.TwoFactorToken, models.TokenPurposeTwoFactor)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	user, err := app.SubmitterModel.GetById(userId)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if !user.Active {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	lockout, err := app.LockoutModel.Get(userId)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if lockout.Locked(time.Now()) {
+		app.logger.Warnw("login to locked account", "user", userId, "ip", ip)
+		app.tooManyRequests(c, time.Until(lockout.LockedUntil))
+		return
+	}
+
+	verified, err := app.verifySecondFactor(userId, req.Code, req.RecoveryCode)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if !verified {
+		app.logger.Warnw("second factor failed", "user", userId, "ip", ip)
+		if _, err = app.LockoutModel.RecordFailure(userId, maxLoginFailures(), lockoutDuration()); err != nil {
+			app.serverError(c, err)
+			return
+		}
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	if err = app.TokenModel.Remove(req.TwoFactorToken); err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+
+	if err = app.LockoutModel.Reset(userId); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.logger.Infow("login succeeded", "user", userId, "ip", ip, "two_factor", true)
+	app.startSession(c, user)
+}
+
+// verifySecondFactor checks a TOTP code or, if no code is given, a recovery code
+func (app *application) verifySecondFactor(userId string, code string, recoveryCode string) (bool, error) {
+	twoFactor, err := app.TwoFactorModel.Get(userId)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !twoFactor.Confirmed {
+		return false, nil
+	}
+
+	if code != "" {
+		step, ok := totp.Verify(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		err = app.TwoFactorModel.UseStep(userId, step)
+	} else if recoveryCode != "" {
+		err = app.TwoFactorModel.UseRecoveryCode(userId, normaliseRecoveryCode(recoveryCode))
+	} else {
+		return false, nil
+	}
+
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// normaliseRecoveryCode strips the formatting recovery codes are shown with
+func normaliseRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// generateRecoveryCodes returns codes for display, like ABCD-EFGH
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, numberOfRecoveryCodes)
+	for i := 0; i < numberOfRecoveryCodes; i++ {
+		raw, err := utils.GenerateUid(recoveryCodeRandomBytes)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+	}
+	return codes, nil
+}
+
+type twoFactorStatus struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
+
+func (app *application) twoFactorStatus(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	enabled, err := app.twoFactorEnabled(user.Id)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	required := false
+	for _, role := range user.Roles {
+		required = required || requiresTwoFactor(role)
+	}
+
+	c.JSON(http.StatusOK, twoFactorStatus{Enabled: enabled, Required: required})
+}
+
+type twoFactorEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// enrolTwoFactor starts enrolment with a new secret. The provisioning URI is meant to be shown as QR code.
+func (app *application) enrolTwoFactor(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	enabled, err := app.twoFactorEnabled(user.Id)
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if enabled {
+		c.JSON(http.StatusConflict, queryError{Message: "two-factor authentication is already enabled", Error: true})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	if err = app.TwoFactorModel.Enrol(user.Id, secret); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, twoFactorEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, twoFactorIssuer, user.Email),
+	})
+}
+
+type twoFactorCode struct {
+	Code string `json:"code"`
+}
+
+type recoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// confirmTwoFactor enables two-factor authentication once the user proves their app generates valid codes
+func (app *application) confirmTwoFactor(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req twoFactorCode
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	twoFactor, err := app.TwoFactorModel.Get(user.Id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, queryError{Message: "enrol before confirming", Error: true})
+			return
+		}
+		app.serverError(c, err)
+		return
+	}
+	if twoFactor.Confirmed {
+		c.JSON(http.StatusConflict, queryError{Message: "two-factor authentication is already enabled", Error: true})
+		return
+	}
+
+	step, valid := totp.Verify(twoFactor.Secret, req.Code, time.Now())
+	if !valid {
+		c.JSON(http.StatusBadRequest, queryError{Message: "invalid code", Error: true})
+		return
+	}
+	if err = app.TwoFactorModel.UseStep(user.Id, step); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		app.serverError(c, err)
+		return
+	}
+	normalised := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalised = append(normalised, normaliseRecoveryCode(code))
+	}
+	if err = app.TwoFactorModel.Confirm(user.Id, normalised); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.logger.Infow("two-factor authentication enabled", "user", user.Id)
+	app.audit(user.Id, models.AuditTwoFactorEnable, user.Id, nil, nil)
+	c.JSON(http.StatusOK, recoveryCodes{RecoveryCodes: codes})
+}
+
+func (app *application) disableTwoFactor(c *gin.Context) {
+	user, ok := app.sessionUser(c)
+	if !ok {
+		return
+	}
+
+	var req passwordConfirmation
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, queryError{Message: err.Error(), Error: true})
+		return
+	}
+
+	if _, ok := app.confirmPassword(c, user.Email, req.Password, http.StatusForbidden); !ok {
+		return
+	}
+
+	if err := app.TwoFactorModel.Disable(user.Id); err != nil {
+		app.serverError(c, err)
+		return
+	}
+
+	app.logger.Infow("two-factor authentication disabled", "user", user.Id)
+	app.audit(user.Id, models.AuditTwoFactorDisable, user.Id, nil, nil)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"secondarymetabolites.org/mibig-api/pkg/totp"
+)
+
+// enableTwoFactor enrols and confirms two-factor authentication, returning the secret and recovery codes
+func enableTwoFactor(t *testing.T, ts string, client *http.Client, token string) (string, []string) {
+	t.Helper()
+	status, body := authenticatedRequest(t, client, "POST", ts+"/api/v1/account/2fa/enrol", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d enrolling, got %d", http.StatusOK, status)
+	}
+	var enrolment twoFactorEnrolment
+	if err := json.Unmarshal(body, &enrolment); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrolment.ProvisioningURI, "otpauth://totp/") {
+		t.Errorf("Unexpected provisioning URI %q", enrolment.ProvisioningURI)
+	}
+
+	code, err := totp.Code(enrolment.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, body = authenticatedRequest(t, client, "POST", ts+"/api/v1/account/2fa/confirm", token, strings.NewReader(`{"code": "`+code+`"}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d confirming, got %d", http.StatusOK, status)
+	}
+	var codes recoveryCodes
+	if err := json.Unmarshal(body, &codes); err != nil {
+		t.Fatal(err)
+	}
+	if len(codes.RecoveryCodes) != numberOfRecoveryCodes {
+		t.Fatalf("Expected %d recovery codes, got %d", numberOfRecoveryCodes, len(codes.RecoveryCodes))
+	}
+	return enrolment.Secret, codes.RecoveryCodes
+}
+
+func loginChallenge(t *testing.T, ts string, client *http.Client) string {
+	t.Helper()
+	status, body := authenticatedRequest(t, client, "POST", ts+"/api/v1/login", "", strings.NewReader(`{"email": "alice@example.com", "password": "secret"}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var challenge twoFactorChallenge
+	if err := json.Unmarshal(body, &challenge); err != nil {
+		t.Fatal(err)
+	}
+	if !challenge.TwoFactorRequired || challenge.TwoFactorToken == "" {
+		t.Fatalf("Expected two-factor challenge, got %s", body)
+	}
+	return challenge.TwoFactorToken
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "curator")
+
+	secret, codes := enableTwoFactor(t, ts.URL, ts.Client(), token)
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/2fa/enrol", token, nil)
+	if status != http.StatusConflict {
+		t.Errorf("Expected %d enrolling twice, got %d", http.StatusConflict, status)
+	}
+
+	// The confirmation used up the current step, so use the next one
+	next, err := totp.Code(secret, time.Now().Add(totp.Period))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name   string
+		Body   func(challenge string) string
+		Status int
+	}{
+		{"wrong code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `", "code": "000000"}`
+		}, http.StatusUnauthorized},
+		{"no code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `"}`
+		}, http.StatusUnauthorized},
+		{"invalid token", func(challenge string) string {
+			return `{"two_factor_token": "invalid", "code": "` + next + `"}`
+		}, http.StatusUnauthorized},
+		{"valid code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `", "code": "` + next + `"}`
+		}, http.StatusOK},
+		{"replayed code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `", "code": "` + next + `"}`
+		}, http.StatusUnauthorized},
+		{"recovery code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `", "recovery_code": "` + strings.ToLower(codes[0]) + `"}`
+		}, http.StatusOK},
+		{"reused recovery code", func(challenge string) string {
+			return `{"two_factor_token": "` + challenge + `", "recovery_code": "` + codes[0] + `"}`
+		}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			challenge := loginChallenge(t, ts.URL, ts.Client())
+			status, body := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/login/2fa", "", strings.NewReader(tt.Body(challenge)))
+			if status != tt.Status {
+				t.Fatalf("Expected %d, got %d", tt.Status, status)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			var tokens tokenResponse
+			if err := json.Unmarshal(body, &tokens); err != nil {
+				t.Fatal(err)
+			}
+			if tokens.Token == "" || tokens.RefreshToken == "" {
+				t.Errorf("Expected access and refresh tokens, got %s", body)
+			}
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	token := newTestToken(t, "curator")
+
+	enableTwoFactor(t, ts.URL, ts.Client(), token)
+
+	status, _ := authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/2fa/disable", token, strings.NewReader(`{"password": "wrong"}`))
+	if status != http.StatusForbidden {
+		t.Errorf("Expected %d for wrong password, got %d", http.StatusForbidden, status)
+	}
+
+	status, _ = authenticatedRequest(t, ts.Client(), "POST", ts.URL+"/api/v1/account/2fa/disable", token, strings.NewReader(`{"password": "secret"}`))
+	if status != http.StatusNoContent {
+		t.Fatalf("Expected %d, got %d", http.StatusNoContent, status)
+	}
+
+	// Without two-factor authentication, the password is enough again
+	login(t, ts.URL+"/api/v1/login", ts.Client(), `{"email": "alice@example.com", "password": "secret"}`)
+}
+
+func TestTwoFactorPolicy(t *testing.T) {
+	_, ts, _ := newTestApp()
+	defer ts.Close()
+	viper.Set("two_factor.required_roles", []string{"admin"})
+	defer viper.Set("two_factor.required_roles", nil)
+	token := newAdminToken(t)
+
+	status, body := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/account/2fa", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, status)
+	}
+	var result twoFactorStatus
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Enabled || !result.Required {
+		t.Errorf("Unexpected status %s", body)
+	}
+
+	tokens := login(t, ts.URL+"/api/v1/login", ts.Client(), `{"email": "admin@example.com", "password": "admin secret"}`)
+	if !tokens.TwoFactorEnrolmentRequired {
+		t.Errorf("Expected login to ask for two-factor enrolment")
+	}
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/users", token, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected %d without two-factor authentication, got %d", http.StatusUnauthorized, status)
+	}
+
+	enableTwoFactor(t, ts.URL, ts.Client(), token)
+
+	if status, _ := authenticatedRequest(t, ts.Client(), "GET", ts.URL+"/api/v1/admin/users", token, nil); status != http.StatusOK {
+		t.Errorf("Expected %d with two-factor authentication, got %d", http.StatusOK, status)
+	}
+}
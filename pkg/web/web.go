@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
@@ -14,16 +15,28 @@ import (
 	zap "go.uber.org/zap"
 
 	"secondarymetabolites.org/mibig-api/pkg/models"
+	"secondarymetabolites.org/mibig-api/pkg/models/cached"
 	"secondarymetabolites.org/mibig-api/pkg/models/postgres"
 )
 
 type application struct {
-	logger         *zap.SugaredLogger
-	MibigModel     models.MibigModel
-	LegacyModel    models.LecagyModel
-	SubmitterModel models.SubmitterModel
-	Mail           models.EmailSender
-	Mux            *gin.Engine
+	logger           *zap.SugaredLogger
+	MibigModel       models.MibigModel
+	MibigCache       *cached.MibigModel
+	LegacyModel      models.LecagyModel
+	SubmitterModel   models.SubmitterModel
+	TokenModel       models.TokenModel
+	SessionModel     models.SessionModel
+	ApiKeyModel      models.ApiKeyModel
+	PublicationModel models.PublicationModel
+	Mail             models.EmailSender
+	Keys             *keySet
+	LockoutModel     models.LockoutModel
+	TwoFactorModel   models.TwoFactorModel
+	AuditModel       models.AuditModel
+	LoginLimiter     *rateLimiter
+	TrustedProxies   []*net.IPNet
+	Mux              *gin.Engine
 }
 
 func Run(debug bool) {
@@ -54,20 +67,65 @@ func Run(debug bool) {
 		Recipient: viper.GetString("mail.recipient"),
 	}
 
+	cacheTTL := time.Hour
+	if viper.IsSet("cache.ttl") {
+		cacheTTL = viper.GetDuration("cache.ttl")
+	}
+	cacheQueries := 100
+	if viper.IsSet("cache.max_queries") {
+		cacheQueries = viper.GetInt("cache.max_queries")
+	}
+	mibigCache := cached.NewMibigModel(&postgres.MibigModel{DB: db}, cacheTTL, cacheQueries)
+
+	keys, err := loadConfiguredKeySet()
+	if err != nil {
+		logger.Fatalf(err.Error())
+	}
+	if keys.signing != nil && len(keys.secret) > 0 {
+		logger.Warnw("accepting tokens signed with server.secret, unset server.accept_secret_tokens once they have expired")
+	}
+
+	loginLimit := 20
+	if viper.IsSet("login.ip_limit") {
+		loginLimit = viper.GetInt("login.ip_limit")
+	}
+	loginWindow := 10 * time.Minute
+	if viper.IsSet("login.ip_window") {
+		loginWindow = viper.GetDuration("login.ip_window")
+	}
+
+	trustedProxies, err := parseTrustedProxies(viper.GetStringSlice("server.trusted_proxies"))
+	if err != nil {
+		logger.Fatalf(err.Error())
+	}
+
 	mailSender := models.NewProductionSender(mailConfig)
 	mux := setupMux(debug, logger.Desugar())
 
 	app := &application{
-		logger:         logger,
-		MibigModel:     &postgres.MibigModel{DB: db},
-		LegacyModel:    &postgres.LegacyModel{DB: legacy_db},
-		SubmitterModel: postgres.NewSubmitterModel(db),
-		Mail:           mailSender,
-		Mux:            mux,
+		logger:           logger,
+		MibigModel:       mibigCache,
+		MibigCache:       mibigCache,
+		LegacyModel:      &postgres.LegacyModel{DB: legacy_db},
+		SubmitterModel:   postgres.NewSubmitterModel(db),
+		TokenModel:       &postgres.TokenModel{DB: db},
+		SessionModel:     &postgres.SessionModel{DB: db},
+		ApiKeyModel:      &postgres.ApiKeyModel{DB: db},
+		PublicationModel: &postgres.PublicationModel{DB: db},
+		Mail:             mailSender,
+		Keys:             keys,
+		LockoutModel:     &postgres.LockoutModel{DB: db},
+		TwoFactorModel:   &postgres.TwoFactorModel{DB: db},
+		AuditModel:       &postgres.AuditModel{DB: db},
+		LoginLimiter:     newRateLimiter(loginLimit, loginWindow),
+		TrustedProxies:   trustedProxies,
+		Mux:              mux,
 	}
 
 	mux = app.routes()
 
+	go app.expireTokens(time.Hour)
+
 	address := fmt.Sprintf("%s:%d", viper.GetString("server.address"), viper.GetInt("server.port"))
 
 	logger.Infow("starting server",
@@ -88,6 +146,8 @@ func setupMux(debug bool, logger *zap.Logger) *gin.Engine {
 		// otherwise use the default Gin logging, which is prettier
 		mux = gin.Default()
 	}
+	// Client supplied forwarding headers can't be trusted, see application.clientIP
+	mux.ForwardedByClientIP = false
 	return mux
 }
 
@@ -0,0 +1,13 @@
+-- Personal API keys. Keys have the form <key_id>.<secret>, only a hash of the secret is stored.
+CREATE TABLE IF NOT EXISTS mibig_submitters.apikeys (
+    key_id text PRIMARY KEY,
+    user_id text NOT NULL REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    name text NOT NULL,
+    secret_hash text NOT NULL,
+    scopes text[] NOT NULL DEFAULT '{}',
+    created timestamp with time zone NOT NULL DEFAULT now(),
+    expires timestamp with time zone,
+    last_used timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS apikeys_user_id_idx ON mibig_submitters.apikeys (user_id);
@@ -0,0 +1,25 @@
+-- Append-only log of privileged actions from the API and the command line.
+-- Actors aren't foreign keys, so entries outlive deleted users. As entries can't be
+-- removed, they must not hold personal data, only user ids and non-personal fields.
+CREATE TABLE IF NOT EXISTS mibig_submitters.audit_log (
+    audit_id bigserial PRIMARY KEY,
+    created timestamp with time zone NOT NULL DEFAULT now(),
+    actor text NOT NULL,
+    action text NOT NULL,
+    target text NOT NULL,
+    before jsonb,
+    after jsonb
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_idx ON mibig_submitters.audit_log (created);
+CREATE INDEX IF NOT EXISTS audit_log_target_idx ON mibig_submitters.audit_log (target);
+
+CREATE OR REPLACE FUNCTION mibig_submitters.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON mibig_submitters.audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON mibig_submitters.audit_log
+    FOR EACH STATEMENT EXECUTE PROCEDURE mibig_submitters.audit_log_append_only();
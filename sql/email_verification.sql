@@ -0,0 +1,4 @@
+-- Whether a submitter's email address was verified. Accounts that were active before
+-- self-registration existed were all created by admins, so they count as verified.
+ALTER TABLE mibig_submitters.submitters ADD COLUMN IF NOT EXISTS email_verified boolean NOT NULL DEFAULT FALSE;
+UPDATE mibig_submitters.submitters SET email_verified = TRUE WHERE active AND NOT email_verified;
@@ -0,0 +1,6 @@
+-- Failed logins per user, so account lockouts survive restarts
+CREATE TABLE IF NOT EXISTS mibig_submitters.lockouts (
+    user_id text PRIMARY KEY REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    failures integer NOT NULL DEFAULT 0,
+    locked_until timestamp with time zone
+);
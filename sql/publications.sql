@@ -0,0 +1,12 @@
+-- Local cache of publication metadata, filled by `mibig-api publications load`
+CREATE TABLE IF NOT EXISTS mibig.publications (
+    pub_id text PRIMARY KEY,
+    title text NOT NULL DEFAULT '',
+    authors text[] NOT NULL DEFAULT '{}',
+    journal text NOT NULL DEFAULT '',
+    year integer NOT NULL DEFAULT 0,
+    volume text NOT NULL DEFAULT '',
+    issue text NOT NULL DEFAULT '',
+    pages text NOT NULL DEFAULT '',
+    doi text NOT NULL DEFAULT ''
+);
@@ -0,0 +1,11 @@
+-- Login sessions. Refresh tokens have the form <session_id>.<secret>, only a hash of the secret is stored.
+CREATE TABLE IF NOT EXISTS mibig_submitters.sessions (
+    session_id text PRIMARY KEY,
+    user_id text NOT NULL REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    secret_hash text NOT NULL,
+    created timestamp with time zone NOT NULL DEFAULT now(),
+    expires timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON mibig_submitters.sessions (user_id);
+CREATE INDEX IF NOT EXISTS sessions_expires_idx ON mibig_submitters.sessions (expires);
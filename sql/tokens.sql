@@ -0,0 +1,9 @@
+-- Single-use tokens mailed to users, e.g. for password resets. Only a hash of the token is stored.
+CREATE TABLE IF NOT EXISTS mibig_submitters.tokens (
+    token_hash text PRIMARY KEY,
+    user_id text NOT NULL REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    purpose text NOT NULL,
+    expires timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tokens_expires_idx ON mibig_submitters.tokens (expires);
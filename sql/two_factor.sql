@@ -0,0 +1,14 @@
+-- TOTP two-factor authentication. The secret has to be stored as is to compute codes,
+-- recovery codes are only stored as hashes.
+CREATE TABLE IF NOT EXISTS mibig_submitters.two_factor (
+    user_id text PRIMARY KEY REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    confirmed boolean NOT NULL DEFAULT FALSE,
+    last_step bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS mibig_submitters.recovery_codes (
+    user_id text NOT NULL REFERENCES mibig_submitters.submitters (user_id) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);